@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+	"uf/mcp/pkg/common"
+	"uf/mcp/pkg/llm"
+	"uf/mcp/pkg/mcp"
+
+	"github.com/tmc/langchaingo/llms/openai"
+)
+
+// Result of a query or a direct tool call, printed as JSON
+type Result struct {
+	Query      string         `json:"query,omitempty"`
+	Tool       string         `json:"tool,omitempty"`
+	Arguments  map[string]any `json:"arguments,omitempty"`
+	Output     string         `json:"output,omitempty"`
+	Answer     string         `json:"answer,omitempty"`
+	Error      string         `json:"error,omitempty"`
+	DurationMs int64          `json:"durationMs"`
+}
+
+var (
+	// created on first use, direct tool calls don't need the LLM
+	model *openai.LLM
+)
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage:\n")
+	fmt.Fprintf(out, "  mcp-cli                                interactive session\n")
+	fmt.Fprintf(out, "  mcp-cli ask \"<query>\"                  answer a single query, JSON output\n")
+	fmt.Fprintf(out, "  mcp-cli --tool NAME [--args '{...}']   call a tool directly without the LLM\n\n")
+	fmt.Fprintf(out, "MCP servers and the LLM are configured with the same environment variables as mcp-client.\n\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	var toolName, toolArgs string
+	var verbose bool
+
+	flag.StringVar(&toolName, "tool", "", "name of the tool to call directly")
+	flag.StringVar(&toolArgs, "args", "{}", "JSON arguments of the tool called with --tool")
+	flag.BoolVar(&verbose, "v", false, "log diagnostics to stderr")
+	flag.Usage = usage
+	flag.Parse()
+
+	if !verbose {
+		log.SetOutput(io.Discard)
+	}
+
+	// Initialize the tools of the configured MCP servers
+	mcp.InitalizeTools()
+	ctx := context.Background()
+
+	switch {
+	case toolName != "":
+		var args map[string]any
+		if err := json.Unmarshal([]byte(toolArgs), &args); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid --args: %v\n", err)
+			os.Exit(2)
+		}
+		exit(printJSON(callTool(ctx, toolName, args)))
+
+	case flag.NArg() == 0:
+		repl(ctx)
+
+	case flag.Arg(0) == "ask" && flag.NArg() == 2:
+		exit(printJSON(ask(ctx, flag.Arg(1))))
+
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+// ask answers a query like the chat: the LLM selects a tool, the tool is called and the
+// LLM formats its output
+func ask(ctx context.Context, query string) *Result {
+	start := time.Now()
+	result := &Result{Query: query}
+	defer func() { result.DurationMs = time.Since(start).Milliseconds() }()
+
+	if model == nil {
+		// common.GetModel exits through log.Fatalf, which is silenced without -v
+		if os.Getenv("LLM_URL") == "" || os.Getenv("LLM_TOKEN") == "" {
+			result.Error = "env variables LLM_URL and LLM_TOKEN are required to answer queries"
+			return result
+		}
+		model = common.GetModel()
+	}
+
+	selected, err := llm.SelectTool(ctx, model, mcp.GetToolListSchema(), query)
+	if err != nil {
+		result.Error = fmt.Sprintf("SelectTool error: %v", err)
+		return result
+	}
+
+	selected, err = mcp.CheckToolArgs(ctx, model, mcp.GetToolListSchema(), query, selected, mcp.DefaultRepairAttempts)
+	if err != nil {
+		result.Tool, result.Arguments = selected.ToolName, selected.ToolArgs
+		result.Error = fmt.Sprintf("Invalid tool arguments: %v", err)
+		return result
+	}
+
+	if selected.ToolName == "none" {
+		answer, err := llm.GenericResponse(ctx, model, query)
+		if err != nil {
+			result.Error = fmt.Sprintf("GenericResponse error: %v", err)
+		}
+		result.Answer = answer
+		return result
+	}
+
+	result.Tool, result.Arguments = selected.ToolName, selected.ToolArgs
+	if len(selected.MissingArgs) > 0 {
+		result.Error = fmt.Sprintf("Some arguments are missing: %s", selected.MissingArgs)
+		return result
+	}
+
+	result.Output, err = mcp.CallTool(ctx, selected)
+	if err != nil {
+		result.Error = fmt.Sprintf("CallTool error: %v", err)
+		return result
+	}
+
+	result.Answer, err = llm.FormatOutput(ctx, model, selected.ToolName, result.Output, query)
+	if err != nil {
+		result.Error = fmt.Sprintf("FormatOutput error: %v", err)
+	}
+	return result
+}
+
+// callTool calls a tool with the given arguments, bypassing the LLM
+func callTool(ctx context.Context, name string, args map[string]any) *Result {
+	start := time.Now()
+	result := &Result{Tool: name, Arguments: args}
+	defer func() { result.DurationMs = time.Since(start).Milliseconds() }()
+
+	// same checks as for arguments chosen by the LLM, without the repair
+	if tool, found := mcp.GetTool(name); found {
+		args = mcp.CoerceArgs(tool, args)
+		result.Arguments = args
+		if violations := mcp.ValidateArgs(tool, args); len(violations) > 0 {
+			result.Error = fmt.Sprintf("Invalid tool arguments: %s", strings.Join(violations, "; "))
+			return result
+		}
+	}
+
+	output, err := mcp.CallTool(ctx, &llm.SelectedToolInfo{ToolName: name, ToolArgs: args})
+	result.Output = output
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// printJSON writes the result to stdout and reports whether it succeeded
+func printJSON(result *Result) bool {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode result: %v\n", err)
+		return false
+	}
+	return result.Error == ""
+}
+
+func exit(ok bool) {
+	if !ok {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
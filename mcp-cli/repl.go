@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"uf/mcp/pkg/mcp"
+
+	"github.com/chzyer/readline"
+)
+
+const (
+	replHelp = `Type a question to have it answered through the MCP tools, or a command:
+  /tools                       list the available tools
+  /tool NAME {"arg": "value"}  call a tool directly without the LLM
+  /json                        toggle printing full JSON results
+  /help                        show this help
+  /exit                        leave (or Ctrl-D)`
+)
+
+// repl runs an interactive session, the input history is kept in ~/.mcp_cli_history
+func repl(ctx context.Context) {
+	historyFile := ""
+	if home, err := os.UserHomeDir(); err == nil {
+		historyFile = filepath.Join(home, ".mcp_cli_history")
+	}
+
+	rl, err := readline.NewEx(&readline.Config{
+		Prompt:          "mcp> ",
+		HistoryFile:     historyFile,
+		InterruptPrompt: "^C",
+		EOFPrompt:       "/exit",
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to start the interactive session: %v\n", err)
+		os.Exit(1)
+	}
+	defer rl.Close()
+
+	fmt.Printf("Connected, %d tools available. Type /help for the commands.\n", len(mcp.GetTools()))
+
+	printFull := false
+	for {
+		line, err := rl.Readline()
+		if errors.Is(err, readline.ErrInterrupt) {
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return
+		}
+
+		line = strings.TrimSpace(line)
+		command, rest, _ := strings.Cut(line, " ")
+
+		switch command {
+		case "":
+			continue
+
+		case "/exit", "/quit":
+			return
+
+		case "/help":
+			fmt.Println(replHelp)
+
+		case "/tools":
+			printTools()
+
+		case "/json":
+			printFull = !printFull
+			fmt.Printf("Printing full JSON results: %v\n", printFull)
+
+		case "/tool":
+			name, argsJSON, _ := strings.Cut(strings.TrimSpace(rest), " ")
+			if name == "" {
+				fmt.Println(`Usage: /tool NAME {"arg": "value"}`)
+				continue
+			}
+			args := map[string]any{}
+			if argsJSON = strings.TrimSpace(argsJSON); argsJSON != "" {
+				if err := json.Unmarshal([]byte(argsJSON), &args); err != nil {
+					fmt.Printf("Invalid arguments: %v\n", err)
+					continue
+				}
+			}
+			printResult(callTool(ctx, name, args), true)
+
+		default:
+			if strings.HasPrefix(command, "/") {
+				fmt.Printf("Unknown command %s, type /help for the commands\n", command)
+				continue
+			}
+			printResult(ask(ctx, line), printFull)
+		}
+	}
+}
+
+func printTools() {
+	tools := mcp.GetTools()
+	names := make([]string, 0, len(tools))
+	descriptions := map[string]string{}
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+		// first line of the description only
+		descriptions[tool.Name], _, _ = strings.Cut(tool.Description, "\n")
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Printf("  %-24s %s\n", name, descriptions[name])
+	}
+}
+
+// printResult prints the answer, or the tool output when there is no answer, and the
+// complete result as JSON when full is true
+func printResult(result *Result, full bool) {
+	if full {
+		printJSON(result)
+		return
+	}
+
+	if result.Tool != "" {
+		fmt.Printf("[%s, %d ms]\n", result.Tool, result.DurationMs)
+	}
+	switch {
+	case result.Error != "":
+		fmt.Printf("Error: %s\n", result.Error)
+	case result.Answer != "":
+		fmt.Println(result.Answer)
+	default:
+		fmt.Println(result.Output)
+	}
+}
@@ -2,6 +2,162 @@ document.addEventListener("DOMContentLoaded", () => {
     const chatMessages = document.getElementById("chat-messages");
     const chatForm = document.getElementById("chat-form");
     const chatInput = document.getElementById("chat-input");
+    const loginPanel = document.getElementById("login-panel");
+    const loginForm = document.getElementById("login-form");
+    const loginError = document.getElementById("login-error");
+    const userInfo = document.getElementById("user-info");
+    const historySidebar = document.getElementById("history-sidebar");
+    const sessionList = document.getElementById("session-list");
+
+    // chat session of the conversation shown, null until the first answer of a new chat
+    let currentSessionId = null;
+
+    // show the login panel instead of the chat until the user is logged in
+    function showLogin(methods) {
+        loginPanel.hidden = false;
+        chatMessages.hidden = true;
+        chatForm.hidden = true;
+        userInfo.hidden = true;
+        loginForm.hidden = !methods.includes("password");
+        document.getElementById("oidc-login").hidden = !methods.includes("oidc");
+    }
+
+    function showChat(user) {
+        loginPanel.hidden = true;
+        chatMessages.hidden = false;
+        chatForm.hidden = false;
+        if (user && user.provider !== "none") {
+            document.getElementById("user-name").textContent = user.name;
+            userInfo.hidden = false;
+        }
+        loadSessions();
+    }
+
+    // list the past conversations in the sidebar, hidden when the history is disabled
+    async function loadSessions() {
+        const response = await fetch("/sessions");
+        if (!response.ok) {
+            historySidebar.hidden = true;
+            return;
+        }
+        historySidebar.hidden = false;
+
+        const sessions = await response.json();
+        sessionList.innerHTML = '';
+        sessions.forEach((session) => {
+            const item = document.createElement("li");
+            item.className = "session-item";
+            if (session.id === currentSessionId) item.classList.add("active");
+
+            const title = document.createElement("span");
+            title.className = "session-title";
+            title.textContent = session.title || "Untitled";
+            title.title = new Date(session.updated).toLocaleString();
+            title.addEventListener("click", () => openSession(session.id));
+
+            const rename = document.createElement("button");
+            rename.type = "button";
+            rename.className = "session-btn";
+            rename.title = "Rename";
+            rename.innerHTML = '<span class="material-icons">edit</span>';
+            rename.addEventListener("click", () => renameSession(session));
+
+            const remove = document.createElement("button");
+            remove.type = "button";
+            remove.className = "session-btn";
+            remove.title = "Delete";
+            remove.innerHTML = '<span class="material-icons">delete</span>';
+            remove.addEventListener("click", () => deleteSession(session));
+
+            const exportLink = document.createElement("a");
+            exportLink.className = "session-btn";
+            exportLink.title = "Export as Markdown";
+            exportLink.href = `/sessions/${encodeURIComponent(session.id)}/export?format=markdown`;
+            exportLink.innerHTML = '<span class="material-icons">download</span>';
+
+            item.append(title, exportLink, rename, remove);
+            sessionList.appendChild(item);
+        });
+    }
+
+    async function openSession(id) {
+        const response = await fetch(`/sessions/${encodeURIComponent(id)}`);
+        if (!response.ok) return;
+
+        const transcript = await response.json();
+        currentSessionId = transcript.id;
+        chatMessages.innerHTML = '';
+        transcript.messages.forEach((msg) => addMessage(msg.role, msg.content));
+        loadSessions();
+    }
+
+    async function renameSession(session) {
+        const title = prompt("Rename conversation", session.title);
+        if (!title || !title.trim()) return;
+
+        await fetch(`/sessions/${encodeURIComponent(session.id)}`, {
+            method: "PUT",
+            headers: { "Content-Type": "application/json" },
+            body: JSON.stringify({ title: title.trim() }),
+        });
+        loadSessions();
+    }
+
+    async function deleteSession(session) {
+        if (!confirm(`Delete "${session.title}"?`)) return;
+
+        await fetch(`/sessions/${encodeURIComponent(session.id)}`, { method: "DELETE" });
+        if (session.id === currentSessionId) {
+            currentSessionId = null;
+            chatMessages.innerHTML = '';
+        }
+        loadSessions();
+    }
+
+    async function checkLogin() {
+        try {
+            const response = await fetch("/auth/info");
+            const info = await response.json();
+            if (info.enabled && !info.user) {
+                showLogin(info.methods);
+            } else {
+                showChat(info.user);
+            }
+        } catch (error) {
+            console.error("Login check error:", error);
+        }
+    }
+
+    loginForm.addEventListener("submit", async (e) => {
+        e.preventDefault();
+        loginError.textContent = "";
+
+        const response = await fetch("/auth/login", {
+            method: "POST",
+            headers: { "Content-Type": "application/json" },
+            body: JSON.stringify({
+                username: document.getElementById("login-username").value,
+                password: document.getElementById("login-password").value,
+            }),
+        });
+
+        document.getElementById("login-password").value = "";
+        if (!response.ok) {
+            const data = await response.json().catch(() => ({}));
+            loginError.textContent = data.Error || "Login failed";
+            return;
+        }
+        showChat(await response.json());
+    });
+
+    document.getElementById("logout-btn").addEventListener("click", async () => {
+        await fetch("/auth/logout", { method: "POST" });
+        chatMessages.innerHTML = '';
+        sessionList.innerHTML = '';
+        historySidebar.hidden = true;
+        currentSessionId = null;
+        checkLogin();
+    });
 
     function appendTypingIndicator () {
         const typingDiv = document.createElement("div");
@@ -70,7 +226,7 @@ document.addEventListener("DOMContentLoaded", () => {
             const response = await fetch("/chat", {
                 method: "POST",
                 headers: { "Content-Type": "application/json" },
-                body: JSON.stringify({ role: "user", content: text }),
+                body: JSON.stringify({ role: "user", content: text, sessionId: currentSessionId || undefined }),
             });
  
             // remove typing indicator after response
@@ -78,12 +234,20 @@ document.addEventListener("DOMContentLoaded", () => {
                 chatMessages.removeChild(typingIndicator);
             }
  
+            if (response.status === 401) {
+                checkLogin();
+                return;
+            }
             if (!response.ok) throw new Error("Network response was not ok");
 
             const data = await response.json();
             if (data.role && data.content) {
                 addMessage(data.role, data.content);
             }
+            if (data.sessionId && data.sessionId !== currentSessionId) {
+                currentSessionId = data.sessionId;
+                loadSessions();
+            }
         } catch (error) {
             addMessage("assistant", "Error: Unable to get response.");
             console.error("Chat error:", error);
@@ -94,8 +258,12 @@ document.addEventListener("DOMContentLoaded", () => {
  
     // New Chat button clears messages and textarea
     document.querySelector('.new-chat-btn').addEventListener('click', () => {
+        currentSessionId = null;
+        loadSessions();
         chatMessages.innerHTML = '';
         chatInput.value = '';
         chatInput.focus();
     });
+
+    checkLogin();
 });
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"uf/mcp/mcp-client/utils"
+	"uf/mcp/pkg/audit"
+	"uf/mcp/pkg/llm"
+	"uf/mcp/pkg/webauth"
+)
+
+var errToolNotAllowed = errors.New("tool not allowed for the user")
+
+// Http Handler querying the audit log, e.g. /audit?identity=alice&tool=ServiceRestarter&since=24h&limit=50
+
+func AuditHandler(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !utils.GetWebAuth().CanReadAuditLog(webauth.UserFromContext(r.Context())) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := audit.Filter{
+		Identity: query.Get("identity"),
+		Tool:     query.Get("tool"),
+		Outcome:  query.Get("outcome"),
+		Limit:    100,
+	}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 0 {
+			http.Error(w, fmt.Sprintf("Invalid limit: %s", v), http.StatusBadRequest)
+			return
+		}
+		filter.Limit = limit
+	}
+
+	// since is either a duration like 24h or an RFC3339 timestamp
+	if v := query.Get("since"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			filter.Since = time.Now().Add(-d)
+		} else if t, err := time.Parse(time.RFC3339, v); err == nil {
+			filter.Since = t
+		} else {
+			http.Error(w, fmt.Sprintf("Invalid since, expected a duration or RFC3339 time: %s", v), http.StatusBadRequest)
+			return
+		}
+	}
+
+	records, err := utils.GetAuditLog().Query(filter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to query audit log: %v", err), http.StatusInternalServerError)
+		log.Printf("Failed to query audit log %v", err)
+		return
+	}
+	if records == nil {
+		records = []audit.Record{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(records); err != nil {
+		log.Printf("Failed to encode response %v", err)
+	}
+}
+
+// auditToolCall writes the audit record of a tool call made on behalf of a chat message
+func auditToolCall(r *http.Request, chatMessage string, tool *llm.SelectedToolInfo, start time.Time, err error) {
+	record := audit.Record{
+		Timestamp:   start.UTC(),
+		Source:      "mcp-client",
+		Identity:    callerIdentity(r),
+		ChatMessage: chatMessage,
+		Tool:        tool.ToolName,
+		Arguments:   audit.Arguments(tool.ToolArgs),
+		Outcome:     audit.OutcomeSuccess,
+		DurationMs:  time.Since(start).Milliseconds(),
+	}
+
+	if err != nil {
+		record.Outcome, record.Error = audit.OutcomeError, err.Error()
+		// calls refused by the user roles, the server policy or read-only mode
+		if err == errToolNotAllowed || strings.Contains(record.Error, `"Code":"PolicyDenied"`) || strings.Contains(record.Error, `"Code":"ReadOnly"`) {
+			record.Outcome = audit.OutcomeDenied
+		}
+	}
+	record.SetObjects(tool.ToolArgs)
+
+	if err := utils.GetAuditLog().Write(record); err != nil {
+		log.Printf("Failed to write audit record %v", err)
+	}
+}
+
+// callerIdentity identifies the user of the chat, the remote address when login is disabled
+func callerIdentity(r *http.Request) string {
+	if user := webauth.UserFromContext(r.Context()); user != nil && user.Provider != "none" {
+		return user.Name
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "addr:" + host
+}
@@ -5,14 +5,21 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strings"
+	"time"
 	"uf/mcp/mcp-client/utils"
+	"uf/mcp/pkg/audit"
+	"uf/mcp/pkg/history"
 	"uf/mcp/pkg/llm"
 	"uf/mcp/pkg/mcp"
+	"uf/mcp/pkg/webauth"
 )
 
 type Message struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
+	// chat session the message belongs to, a new session is started when empty
+	SessionID string `json:"sessionId,omitempty"`
 }
 
 // Http Handler for chat
@@ -21,10 +28,8 @@ func ChatHandler(w http.ResponseWriter, r *http.Request) {
 
 	log.Printf("ChatHandler is processing the userMsg")
 
-	// Allow CORS for frontend
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "POST")
-	w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type")
+	received := time.Now()
+	var toolCall *history.ToolCall
 
 	var userMsg Message
 	var output string
@@ -39,17 +44,33 @@ func ChatHandler(w http.ResponseWriter, r *http.Request) {
 		log.Printf("Invalid message format")
 	}
 
-	if exception == "" { // if initial validation is successful
+	validRequest := exception == ""
+
+	if exception == "" && strings.HasPrefix(strings.TrimSpace(userMsg.Content), "/") {
+		// slash commands are handled without the LLM
+		output, toolCall, exception = slashCommand(r, userMsg.Content)
+	} else if exception == "" { // if initial validation is successful
 		ctx := r.Context()
+		user := webauth.UserFromContext(ctx)
 		log.Printf("model: %v", utils.GetModel())
 
+		// only offer the tools the user may invoke
+		canUseTool := func(toolName string) bool {
+			return utils.GetWebAuth().CanUseTool(user, toolName)
+		}
+
 		// Select a tool and get the arguments to the selected tool
-		selectToolResp, err := llm.SelectTool(ctx, utils.GetModel(), mcp.GetToolListSchema(), userMsg.Content)
+		toolListSchema := mcp.GetToolListSchemaFor(canUseTool)
+		selectToolResp, err := llm.SelectTool(ctx, utils.GetModel(), toolListSchema, userMsg.Content)
 		if err != nil {
 			exception = fmt.Sprintf("SelectTool error: %v", err)
 			log.Printf("SelectTool error %v", err)
+		} else if selectToolResp, err = mcp.CheckToolArgs(ctx, utils.GetModel(), toolListSchema, userMsg.Content, selectToolResp, utils.GetRepairAttempts()); err != nil {
+			// the arguments still violate the inputSchema after the repair attempts
+			exception = fmt.Sprintf("Invalid tool arguments: %v", err)
+			log.Printf("CheckToolArgs error %v", err)
 		} else {
-			fmt.Printf("DBG ChatHandler>> selectToolResp: %v\n", selectToolResp)
+			log.Printf("ChatHandler selected tool: %v", selectToolResp)
 
 			// if no tool available to answer the query, get a generic response from LLM
 			if selectToolResp.ToolName == "none" {
@@ -59,12 +80,28 @@ func ChatHandler(w http.ResponseWriter, r *http.Request) {
 				// if some arguments missing, report the missing arguments ...
 				//log.Printf("DBG ChatHandler>> selectToolResp: %v\n", selectToolResp)
 
-				if len(selectToolResp.MissingArgs) > 0 {
+				if !canUseTool(selectToolResp.ToolName) {
+					exception = fmt.Sprintf("You are not allowed to use the tool %s", selectToolResp.ToolName)
+					log.Printf("User %s is not allowed to use %s", user.Name, selectToolResp.ToolName)
+					auditToolCall(r, userMsg.Content, selectToolResp, time.Now(), errToolNotAllowed)
+				} else if len(selectToolResp.MissingArgs) > 0 {
 					exception = fmt.Sprintf("Some arguments are missing: %s", selectToolResp.MissingArgs)
 				} else {
 					// Call the selected tool
+					start := time.Now()
 					toolOutput, err := mcp.CallTool(ctx, selectToolResp)
 					log.Printf("toolOutput: %v\n", toolOutput)
+					auditToolCall(r, userMsg.Content, selectToolResp, start, err)
+
+					toolCall = &history.ToolCall{
+						Name:       selectToolResp.ToolName,
+						Arguments:  audit.Arguments(selectToolResp.ToolArgs),
+						Output:     toolOutput,
+						DurationMs: time.Since(start).Milliseconds(),
+					}
+					if err != nil {
+						toolCall.Error = err.Error()
+					}
 
 					//fmt.Printf("DBG ChatHandler>> toolOutput: %v\n", toolOutput)
 					if err != nil {
@@ -96,6 +133,21 @@ func ChatHandler(w http.ResponseWriter, r *http.Request) {
 		Content: content,
 	}
 
+	// keep the question and the answer in the chat history
+	if validRequest {
+		answer := history.Message{
+			Role:       "assistant",
+			Content:    content,
+			DurationMs: time.Since(received).Milliseconds(),
+			ToolCall:   toolCall,
+		}
+		sessionID, err := saveExchange(r, userMsg.SessionID, userMsg.Content, answer)
+		if err != nil {
+			log.Printf("Failed to save chat history %v", err)
+		}
+		assistantMsg.SessionID = sessionID
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 
 	if err := json.NewEncoder(w).Encode(assistantMsg); err != nil {
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"uf/mcp/mcp-client/utils"
+	"uf/mcp/pkg/history"
+	"uf/mcp/pkg/webauth"
+)
+
+// Http Handler of the chat history
+//
+//	GET    /sessions       list the sessions of the user
+//	GET    /sessions/{id}  transcript of a session
+//	PUT    /sessions/{id}  rename a session, body {"title": "..."}
+//	DELETE /sessions/{id}  delete a session
+//	GET    /sessions/{id}/export?format=markdown|json  download a session
+
+func SessionsHandler(w http.ResponseWriter, r *http.Request) {
+
+	store := utils.GetHistory()
+	if store == nil {
+		http.Error(w, "Chat history is not enabled", http.StatusNotFound)
+		return
+	}
+
+	user := historyUser(r)
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/sessions"), "/")
+
+	if exportID, found := strings.CutSuffix(id, "/export"); found {
+		exportSession(w, r, user, exportID)
+		return
+	}
+
+	var result any
+	var err error
+
+	switch {
+	case id == "" && r.Method == http.MethodGet:
+		result, err = store.ListSessions(user)
+
+	case id != "" && r.Method == http.MethodGet:
+		result, err = store.GetTranscript(user, id)
+
+	case id != "" && r.Method == http.MethodPut:
+		var body struct {
+			Title string `json:"title"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON payload: %v", err), http.StatusBadRequest)
+			return
+		}
+		title := strings.TrimSpace(body.Title)
+		if title == "" {
+			http.Error(w, "Title must not be empty", http.StatusBadRequest)
+			return
+		}
+		result, err = store.RenameSession(user, id, title)
+
+	case id != "" && r.Method == http.MethodDelete:
+		if err = store.DeleteSession(user, id); err == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if errors.Is(err, history.ErrNotFound) {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Printf("Chat history error %v", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Printf("Failed to encode response %v", err)
+	}
+}
+
+// exportSession downloads a session as Markdown or JSON, including the tool calls
+func exportSession(w http.ResponseWriter, r *http.Request, user, id string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "markdown"
+	}
+	if format != "markdown" && format != "json" {
+		http.Error(w, fmt.Sprintf("Invalid format %s, expected markdown or json", format), http.StatusBadRequest)
+		return
+	}
+
+	transcript, err := utils.GetHistory().GetTranscript(user, id)
+	if errors.Is(err, history.ErrNotFound) {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Printf("Chat history error %v", err)
+		return
+	}
+	exportedBy := "anonymous"
+	if u := webauth.UserFromContext(r.Context()); u != nil {
+		exportedBy = u.Name
+	}
+	export := history.NewExport(exportedBy, transcript)
+
+	if format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="chat-%s.json"`, transcript.ID))
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(export); err != nil {
+			log.Printf("Failed to encode response %v", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="chat-%s.md"`, transcript.ID))
+	w.Write([]byte(export.Markdown()))
+}
+
+// saveExchange appends a question and its answer to a session, starting a new session
+// when sessionID is empty or unknown. It returns the id of the session.
+func saveExchange(r *http.Request, sessionID, question string, answer history.Message) (string, error) {
+	store := utils.GetHistory()
+	if store == nil {
+		return "", nil
+	}
+
+	user := historyUser(r)
+	err := history.ErrNotFound
+	if sessionID != "" {
+		err = store.AppendMessage(user, sessionID, history.Message{Role: "user", Content: question})
+	}
+	if errors.Is(err, history.ErrNotFound) {
+		session, err := store.CreateSession(user, "")
+		if err != nil {
+			return "", err
+		}
+		sessionID = session.ID
+		if err := store.AppendMessage(user, sessionID, history.Message{Role: "user", Content: question}); err != nil {
+			return sessionID, err
+		}
+	} else if err != nil {
+		return sessionID, err
+	}
+
+	return sessionID, store.AppendMessage(user, sessionID, answer)
+}
+
+// historyUser keys the history by the provider and subject of the logged in user
+func historyUser(r *http.Request) string {
+	if user := webauth.UserFromContext(r.Context()); user != nil && user.Provider != "none" {
+		return user.ID()
+	}
+	return "anonymous"
+}
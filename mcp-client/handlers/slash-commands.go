@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+	"uf/mcp/mcp-client/utils"
+	"uf/mcp/pkg/audit"
+	"uf/mcp/pkg/history"
+	"uf/mcp/pkg/llm"
+	"uf/mcp/pkg/mcp"
+	"uf/mcp/pkg/webauth"
+)
+
+const (
+	slashHelp = `Commands:
+/tools                         list the tools you may use
+/tool NAME key=value ...       call a tool directly and show its raw output
+/help                          show this help
+
+Quote values containing spaces, e.g. /tool ObjectDescriber kind=Deployment name="my app"`
+)
+
+// slashCommand handles chat input starting with '/' without the LLM. It returns the
+// output, the tool call made if any, and an exception message on failure.
+func slashCommand(r *http.Request, input string) (string, *history.ToolCall, string) {
+	fields, err := splitCommand(input)
+	if err != nil {
+		return "", nil, err.Error()
+	}
+
+	user := webauth.UserFromContext(r.Context())
+
+	switch fields[0] {
+	case "/help":
+		return slashHelp, nil, ""
+
+	case "/tools":
+		var lines []string
+		for _, tool := range mcp.GetTools() {
+			if !utils.GetWebAuth().CanUseTool(user, tool.Name) {
+				continue
+			}
+			description, _, _ := strings.Cut(tool.Description, "\n")
+			lines = append(lines, fmt.Sprintf("%s: %s\n    %s", tool.Name, description, mcp.ArgsUsage(tool)))
+		}
+		sort.Strings(lines)
+		if len(lines) == 0 {
+			return "No tools available", nil, ""
+		}
+		return strings.Join(lines, "\n") + "\n\n(* marks required arguments)", nil, ""
+
+	case "/tool":
+		if len(fields) < 2 {
+			return "", nil, "Usage: /tool NAME key=value ..."
+		}
+		return slashToolCall(r, input, fields[1], fields[2:])
+
+	default:
+		return "", nil, fmt.Sprintf("Unknown command %s, type /help for the commands", fields[0])
+	}
+}
+
+func slashToolCall(r *http.Request, input, toolName string, pairs []string) (string, *history.ToolCall, string) {
+	user := webauth.UserFromContext(r.Context())
+
+	tool, found := mcp.GetTool(toolName)
+	if !found {
+		return "", nil, fmt.Sprintf("Unknown tool %s, type /tools for the available tools", toolName)
+	}
+
+	selected := &llm.SelectedToolInfo{ToolName: tool.Name}
+	if !utils.GetWebAuth().CanUseTool(user, tool.Name) {
+		log.Printf("User %s is not allowed to use %s", user.Name, tool.Name)
+		auditToolCall(r, input, selected, time.Now(), errToolNotAllowed)
+		return "", nil, fmt.Sprintf("You are not allowed to use the tool %s", tool.Name)
+	}
+
+	args, err := mcp.ParseArgs(tool, pairs)
+	if err != nil {
+		return "", nil, fmt.Sprintf("%v\nUsage: /tool %s %s", err, tool.Name, mcp.ArgsUsage(tool))
+	}
+	if violations := mcp.ValidateArgs(tool, args); len(violations) > 0 {
+		return "", nil, fmt.Sprintf("Invalid arguments: %s\nUsage: /tool %s %s", strings.Join(violations, "; "), tool.Name, mcp.ArgsUsage(tool))
+	}
+	selected.ToolArgs = args
+
+	start := time.Now()
+	toolOutput, err := mcp.CallTool(r.Context(), selected)
+	auditToolCall(r, input, selected, start, err)
+
+	toolCall := &history.ToolCall{
+		Name:       tool.Name,
+		Arguments:  audit.Arguments(args),
+		Output:     toolOutput,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		log.Printf("CallTool error %v", err)
+		toolCall.Error = err.Error()
+		return "", toolCall, fmt.Sprintf("CallTool error: %v", err)
+	}
+	return toolOutput, toolCall, ""
+}
+
+// splitCommand splits a command line on spaces, honoring single and double quotes
+func splitCommand(input string) ([]string, error) {
+	var fields []string
+	var current strings.Builder
+	var quote rune
+	inField := false
+
+	for _, r := range strings.TrimSpace(input) {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote, inField = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inField {
+				fields = append(fields, current.String())
+				current.Reset()
+				inField = false
+			}
+		default:
+			current.WriteRune(r)
+			inField = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in command")
+	}
+	if inField {
+		fields = append(fields, current.String())
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty command")
+	}
+	return fields, nil
+}
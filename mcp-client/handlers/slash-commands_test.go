@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{"/tools", []string{"/tools"}, false},
+		{"  /tool PodFinder   namespace=shop  ", []string{"/tool", "PodFinder", "namespace=shop"}, false},
+		{"/tool X\tnamespace=shop\nname=cart", []string{"/tool", "X", "namespace=shop", "name=cart"}, false},
+		{`/tool X message="hello world"`, []string{"/tool", "X", "message=hello world"}, false},
+		{`/tool X 'label=app=cart web'`, []string{"/tool", "X", "label=app=cart web"}, false},
+		{`/tool X quote="it's"`, []string{"/tool", "X", "quote=it's"}, false},
+		{`/tool X name=""`, []string{"/tool", "X", "name="}, false},
+		{`/tool X ''`, []string{"/tool", "X", ""}, false},
+		{`/tool X name="cart`, nil, true},
+		{"   ", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := splitCommand(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("splitCommand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
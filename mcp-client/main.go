@@ -8,14 +8,30 @@ import (
 	"uf/mcp/mcp-client/handlers"
 	"uf/mcp/mcp-client/utils"
 	"uf/mcp/pkg/mcp"
+	"uf/mcp/pkg/webauth"
 )
 
 func main() {
 	// Declare the static file directory & routes
 	http.Handle("/", http.FileServer(http.Dir("./AppRoot/static")))
 
+	// Login endpoints
+	webAuth := utils.GetWebAuth()
+	http.Handle("/auth/info", cors(http.HandlerFunc(webAuth.InfoHandler)))
+	http.Handle("/auth/login", cors(http.HandlerFunc(webAuth.LoginHandler)))
+	http.Handle("/auth/logout", cors(http.HandlerFunc(webAuth.LogoutHandler)))
+	http.HandleFunc("/auth/oidc/login", webAuth.OIDCLoginHandler)
+	http.HandleFunc("/auth/oidc/callback", webAuth.OIDCCallbackHandler)
+
 	// REST API endpoint for chat
-	http.HandleFunc("/chat", handlers.ChatHandler)
+	http.Handle("/chat", api(http.HandlerFunc(handlers.ChatHandler)))
+
+	// REST API endpoints of the chat history
+	http.Handle("/sessions", api(http.HandlerFunc(handlers.SessionsHandler)))
+	http.Handle("/sessions/", api(http.HandlerFunc(handlers.SessionsHandler)))
+
+	// REST API endpoint querying the audit log of tool calls
+	http.Handle("/audit", api(http.HandlerFunc(handlers.AuditHandler)))
 
 	// Bring up the http listener
 	address := ":8080"
@@ -30,6 +46,15 @@ func main() {
 	}
 }
 
+// api restricts a REST endpoint to logged in users and the allowed CORS origins
+func api(handler http.Handler) http.Handler {
+	return cors(utils.GetWebAuth().RequireUser(handler))
+}
+
+func cors(handler http.Handler) http.Handler {
+	return webauth.CORS(utils.GetAllowedOrigins(), handler)
+}
+
 func init() {
 	// Initialize the list of tools avaibale to this application
 	// init() method not used in utils package to enable testing of individual functions
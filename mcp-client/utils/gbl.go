@@ -1,6 +1,10 @@
 package utils
 
 import (
+	"uf/mcp/pkg/audit"
+	"uf/mcp/pkg/history"
+	"uf/mcp/pkg/webauth"
+
 	"github.com/ThinkInAIXYZ/go-mcp/client"
 	"github.com/tmc/langchaingo/llms/openai"
 )
@@ -29,6 +33,15 @@ var (
 	AppRoot    string
 	mcpClients map[string]*client.Client
 	model      *openai.LLM
+	auditLog   *audit.Logger
+	webAuth    *webauth.Manager
+	chatStore  *history.Store
+
+	// origins allowed to call the REST API from the browser
+	allowedOrigins []string
+
+	// times the LLM is asked to repair tool arguments violating the inputSchema
+	repairAttempts int
 )
 
 func GetMCPClients() map[string]*client.Client {
@@ -39,8 +52,33 @@ func GetModel() *openai.LLM {
 	return model
 }
 
+// GetAuditLog returns the audit logger, nil when auditing is disabled
+func GetAuditLog() *audit.Logger {
+	return auditLog
+}
+
+func GetWebAuth() *webauth.Manager {
+	return webAuth
+}
+
+// GetHistory returns the chat history store, nil when the history is disabled
+func GetHistory() *history.Store {
+	return chatStore
+}
+
+func GetAllowedOrigins() []string {
+	return allowedOrigins
+}
+
+func GetRepairAttempts() int {
+	return repairAttempts
+}
+
 func Stop() {
 	for _, c := range mcpClients {
 		c.Close()
 	}
+	if chatStore != nil {
+		chatStore.Close()
+	}
 }
@@ -3,7 +3,13 @@ package utils
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"uf/mcp/pkg/audit"
 	"uf/mcp/pkg/common"
+	"uf/mcp/pkg/history"
+	"uf/mcp/pkg/mcp"
+	"uf/mcp/pkg/webauth"
 )
 
 func InitializeConfiguration() {
@@ -20,4 +26,62 @@ func InitializeConfiguration() {
 
 	// Get LLM ...
 	model = common.GetModel()
+	repairAttempts = envInt("ARGS_REPAIR_ATTEMPTS", mcp.DefaultRepairAttempts)
+
+	// Get audit log, AUDIT_LOG="" disables it ...
+	auditFile := "mcp-client-audit.jsonl"
+	if v, found := os.LookupEnv("AUDIT_LOG"); found {
+		auditFile = v
+	}
+	if auditFile != "" {
+		var err error
+		auditLog, err = audit.NewLogger(auditFile, envInt("AUDIT_MAX_SIZE_MB", 10), envInt("AUDIT_MAX_BACKUPS", 5))
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		log.Printf("Writing audit log to %s", auditFile)
+	}
+
+	// Get chat history, HISTORY_DB="" disables it ...
+	historyFile := "chat-history.db"
+	if v, found := os.LookupEnv("HISTORY_DB"); found {
+		historyFile = v
+	}
+	if historyFile != "" {
+		var err error
+		chatStore, err = history.Open(historyFile)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		log.Printf("Storing chat history in %s", historyFile)
+	}
+
+	// Get user login, CHAT_AUTH_CONFIG unset leaves the chat open to anyone ...
+	var err error
+	webAuth, err = webauth.NewManager(os.Getenv("CHAT_AUTH_CONFIG"))
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	if !webAuth.Enabled() {
+		log.Print("WARNING: env variable CHAT_AUTH_CONFIG not set, the chat is open to anyone")
+	}
+
+	// Get CORS origins, a comma separated list, e.g. http://localhost:3000 ...
+	for _, origin := range strings.Split(os.Getenv("CORS_ALLOWED_ORIGINS"), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			allowedOrigins = append(allowedOrigins, origin)
+		}
+	}
+}
+
+func envInt(name string, defaultValue int) int {
+	v, found := os.LookupEnv(name)
+	if !found {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Fatalf("env variable %s must be a number: %v", name, err)
+	}
+	return n
 }
@@ -1,23 +1,66 @@
+// Command mcp-server serves the Kubernetes tools over the MCP streamable HTTP transport.
+//
+// Every option is a command line flag, see -help. Read-only mode can also be enabled
+// through the environment, e.g. in the Deployment of an instance shared with the org:
+//
+//	MCP_READ_ONLY=true   same as -read-only
 package main
 
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"strings"
 	"uf/mcp/mcp-server/tools"
+	"uf/mcp/pkg/audit"
+	"uf/mcp/pkg/auth"
+	"uf/mcp/pkg/kube"
 
 	"github.com/ThinkInAIXYZ/go-mcp/protocol"
 	"github.com/ThinkInAIXYZ/go-mcp/server"
 	"github.com/ThinkInAIXYZ/go-mcp/transport"
 )
 
+var (
+	// hide tools the service account is not allowed to use instead of marking them
+	hideForbidden bool
+
+	// namespace where tool permissions denied cluster-wide are checked again
+	permissionsNamespace string
+
+	// file restricting the namespaces tools may operate on
+	policyFile string
+
+	// refuse to register or execute tools that change the cluster, tools offering a dry
+	// run are registered with only their dry run allowed
+	readOnly bool
+
+	// authentication of the MCP endpoint
+	tokenFile   string
+	tlsCert     string
+	tlsKey      string
+	clientCA    string
+	requireCert bool
+
+	// JSON-lines audit log of every tool call
+	auditFile       string
+	auditMaxSize    int
+	auditMaxBackups int
+)
+
 func main() {
 	// Get MCP Server instance...
-	mcpServer := getMcpServer()
+	mcpServer, httpServer := getMcpServer()
 
 	// Register AddNumbers tools
 	registerTools(mcpServer)
 
+	// Long running tools report their progress through the server
+	tools.SetProgressNotifier(mcpServer.SendNotification4Progress)
+
 	// start mcp Server
 	go func() {
 		if err := mcpServer.Run(); err != nil {
@@ -25,7 +68,23 @@ func main() {
 		}
 	}()
 
+	// serve the mcp endpoint behind the authentication middleware
+	go func() {
+		log.Printf("Listening on %s", httpServer.Addr)
+
+		var err error
+		if tlsCert != "" {
+			err = httpServer.ListenAndServeTLS(tlsCert, tlsKey)
+		} else {
+			err = httpServer.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			log.Fatalf("MCP endpoint failed: %v", err)
+		}
+	}()
+
 	defer mcpServer.Shutdown(context.Background())
+	defer httpServer.Shutdown(context.Background())
 
 	// Add logic to listen for kill signal & terminate the program
 	waitChl := make(chan struct{})
@@ -34,35 +93,145 @@ func main() {
 }
 
 func registerTools(mcpServer *server.Server) {
+	// check the RBAC permissions of every tool once at startup
+	denied := map[string][]string{}
+	if report, err := tools.CheckServerPermissions(permissionsNamespace); err != nil {
+		log.Printf("Unable to check tool permissions: %v", err)
+	} else {
+		for _, entry := range report {
+			if !entry.Allowed {
+				denied[entry.Tool] = entry.Denied
+			}
+		}
+	}
+
+	register := func(tool *protocol.Tool, handler server.ToolHandlerFunc) {
+		if readOnly && tools.IsMutating(tool.Name) {
+			if !tools.HasDryRun(tool.Name) {
+				log.Printf("Read-only mode, skipping mutating tool %s", tool.Name)
+				return
+			}
+			tool.Description += "\nThe MCP server runs in read-only mode: only the dry run of this tool is allowed."
+		}
+		if missing, found := denied[tool.Name]; found {
+			if hideForbidden {
+				log.Printf("Hiding tool %s, missing permissions: %s", tool.Name, strings.Join(missing, ", "))
+				return
+			}
+			log.Printf("Tool %s is missing permissions: %s", tool.Name, strings.Join(missing, ", "))
+			tool.Description += fmt.Sprintf("\nWARNING: the MCP server is not permitted to %s, this tool is expected to fail.", strings.Join(missing, ", "))
+		}
+		mcpServer.RegisterTool(tool, tools.WithAudit(tool, tools.WithPolicy(tool, handler)))
+	}
 
-	mcpServer.RegisterTool(tools.GetCalculatorTool())
-	mcpServer.RegisterTool(tools.GetServiceFinderTool())
-	mcpServer.RegisterTool(tools.GetDeploymentFinderTool())
-	mcpServer.RegisterTool(tools.GetNamespaceFinderTool())
-	mcpServer.RegisterTool(tools.GetIngressFinderTool())
-	mcpServer.RegisterTool(tools.GetServiceRestarterTool())
-	mcpServer.RegisterTool(tools.GetPodCpuMemoryViewerTool())
+	register(tools.GetCalculatorTool())
+	register(tools.GetServiceFinderTool())
+	register(tools.GetDeploymentFinderTool())
+	register(tools.GetNamespaceFinderTool())
+	register(tools.GetIngressFinderTool())
+	register(tools.GetServiceRestarterTool())
+	register(tools.GetPodCpuMemoryViewerTool())
+	register(tools.GetPodFinderTool())
+	register(tools.GetObjectDescriberTool())
+	register(tools.GetWorkloadDiagnoserTool())
+	register(tools.GetNodeFinderTool())
+	register(tools.GetNodeMetricsViewerTool())
+	register(tools.GetQuotaViewerTool())
+	register(tools.GetConfigMapViewerTool())
+	register(tools.GetSecretViewerTool())
+	register(tools.GetCertExpiryScannerTool())
+	register(tools.GetServiceInspectorTool())
+	register(tools.GetJobFinderTool())
+	register(tools.GetCronJobFinderTool())
+	register(tools.GetCronJobTriggerTool())
+	register(tools.GetHPAViewerTool())
+	register(tools.GetImageInventoryTool())
+	register(tools.GetNodeCordonTool())
+	register(tools.GetNodeDrainTool())
+	register(tools.GetManifestApplierTool())
+	register(tools.GetPermissionsReportTool())
 }
 
-func getMcpServer() *server.Server {
+func getMcpServer() (*server.Server, *http.Server) {
 	// define flag variables for command line arguments
 	var addr string
 	var endpoint string
 
 	flag.StringVar(&addr, "addr", ":9090", "listen address")
 	flag.StringVar(&endpoint, "endpoint", "/mcp", "endpoint")
+	flag.BoolVar(&hideForbidden, "hide-forbidden", false, "hide tools not permitted by RBAC instead of marking them")
+	flag.StringVar(&permissionsNamespace, "permissions-namespace", podNamespace(), "namespace where tool permissions denied cluster-wide are checked again, defaults to the namespace of the pod")
+	flag.StringVar(&policyFile, "policy", "", "namespace policy file (YAML or JSON)")
+	flag.BoolVar(&readOnly, "read-only", os.Getenv("MCP_READ_ONLY") == "true", "only register read-only tools, mutating tools with a dry run are limited to it (env MCP_READ_ONLY=true)")
+	flag.StringVar(&tokenFile, "tokens", "", "file mapping bearer tokens to caller identities (YAML or JSON)")
+	flag.StringVar(&tlsCert, "tls-cert", "", "TLS certificate of the endpoint")
+	flag.StringVar(&tlsKey, "tls-key", "", "TLS private key of the endpoint")
+	flag.StringVar(&clientCA, "client-ca", "", "CA bundle verifying client certificates (mTLS), requires -tls-cert")
+	flag.BoolVar(&requireCert, "require-client-cert", false, "reject clients without a verified certificate, even with a bearer token")
+	flag.StringVar(&auditFile, "audit-log", "mcp-server-audit.jsonl", "JSON-lines audit log of tool calls, empty to disable")
+	flag.IntVar(&auditMaxSize, "audit-max-size", 10, "size in MB after which the audit log is rotated")
+	flag.IntVar(&auditMaxBackups, "audit-max-backups", 5, "number of rotated audit logs to keep")
 	flag.Parse()
 
-	// setup a streamable http server transport
-	streamableTransport := transport.NewStreamableHTTPServerTransport(
-		addr,
-		transport.WithStreamableHTTPServerTransportOptionEndpoint(endpoint),
-	)
+	if err := kube.CheckConfig(); err != nil {
+		log.Fatalf("Failed to load the Kubernetes configuration: %v", err)
+	}
+
+	tools.SetReadOnly(readOnly)
+
+	if policyFile != "" {
+		if err := tools.LoadPolicy(policyFile); err != nil {
+			log.Fatalf("%v", err)
+		}
+	}
+
+	if auditFile != "" {
+		auditLog, err := audit.NewLogger(auditFile, auditMaxSize, auditMaxBackups)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		tools.SetAuditLog(auditLog)
+		log.Printf("Writing audit log to %s", auditFile)
+	}
+
+	if clientCA != "" && tlsCert == "" {
+		log.Fatalf("-client-ca requires -tls-cert and -tls-key")
+	}
+
+	authenticator, err := auth.NewAuthenticator(tokenFile, clientCA != "")
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	if !authenticator.Enabled() {
+		log.Print("WARNING: no authentication configured, the MCP endpoint is open to anyone")
+	}
+
+	// setup a streamable http transport served by our own http server,
+	// so that requests go through the authentication middleware
+	streamableTransport, handler, err := transport.NewStreamableHTTPServerTransportAndHandler()
+	if err != nil {
+		log.Panicf("new streamable http transport error: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(endpoint, authenticator.Middleware(handler.HandleMCP()))
+
+	httpServer := &http.Server{
+		Addr:    addr,
+		Handler: mux,
+	}
+
+	if clientCA != "" {
+		httpServer.TLSConfig, err = auth.ServerTLSConfig(clientCA, requireCert)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+	}
 
 	// new mcp server
 	mcpServer, err := server.NewServer(streamableTransport,
 		server.WithServerInfo(protocol.Implementation{
-			Name:    "minikube-mcp-server",
+			Name:    serverName(),
 			Version: "1.0.0",
 		}),
 	)
@@ -71,5 +240,24 @@ func getMcpServer() *server.Server {
 		log.Panicf("new mcpServer error: %v", err)
 	}
 
-	return mcpServer
+	return mcpServer, httpServer
+}
+
+func serverName() string {
+	if readOnly {
+		return "minikube-mcp-server-read-only"
+	}
+	return "minikube-mcp-server"
+}
+
+// podNamespace returns the namespace the server runs in, empty outside of a cluster
+func podNamespace() string {
+	if namespace := os.Getenv("POD_NAMESPACE"); namespace != "" {
+		return namespace
+	}
+	data, err := os.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/namespace")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
 }
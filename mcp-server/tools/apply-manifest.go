@@ -0,0 +1,128 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"uf/mcp/pkg/kube"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+)
+
+type ManifestApplier struct {
+	Manifest     string `json:"manifest" description:"YAML (or JSON) manifest of one or more Kubernetes objects, separated by ---" required:"true"`
+	Namespace    string `json:"namespace" description:"Optional Namespace for namespaced objects that do not specify one"`
+	Apply        bool   `json:"apply" description:"Set to true to actually apply the manifest. Defaults to false, a server-side dry-run that only reports the diff"`
+	FieldManager string `json:"fieldManager" description:"Name of the field manager recorded on the applied fields. Required when apply is true"`
+}
+
+// PartialApply is the result of a manifest whose objects were only partially applied
+type PartialApply struct {
+	Error   string             `json:"Error"`
+	Applied []kube.ApplyResult `json:"applied"`
+}
+
+// Name of the tool
+func (m *ManifestApplier) Name() string {
+	return "ManifestApplier"
+}
+
+// Description of the tool
+func (m *ManifestApplier) Description() string {
+	desc := []string{
+		"Tool to server-side apply a Kubernetes manifest.",
+		"By default the manifest is applied with dryRun=All: nothing is changed and a field-level diff against the live objects is returned.",
+		"With apply set to true and an explicit fieldManager the manifest is applied for real.",
+		"This tool modifies the cluster when apply is true.",
+	}
+	return strings.Join(desc, "\n")
+}
+
+func GetManifestApplierTool() (*protocol.Tool, server.ToolHandlerFunc) {
+	log.Print("Initializing ManifestApplier tool")
+
+	toolStruct := ManifestApplier{}
+
+	tool, err := protocol.NewTool(
+		toolStruct.Name(),
+		toolStruct.Description(),
+		toolStruct,
+	)
+	if err != nil {
+		log.Fatalf("Failed to create tool: %v", err)
+	}
+
+	return tool, handleManifestApplier
+}
+
+// Tool execution logic
+func handleManifestApplier(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+	var request ManifestApplier
+
+	if err := protocol.VerifyAndUnmarshal(req.RawArguments, &request); err != nil {
+		return nil, err
+	}
+
+	if readOnly && request.Apply {
+		return refuseReadOnly("ManifestApplier", "only the dry-run diff is available, apply must be false"), nil
+	}
+
+	var results []kube.ApplyResult
+	var err error
+	if request.Apply && request.FieldManager == "" {
+		err = fmt.Errorf("fieldManager is required to apply a manifest")
+	} else {
+		results, err = kube.ApplyManifest(request.Manifest, request.Namespace, request.FieldManager, !request.Apply, namespaceCheck("ManifestApplier"))
+	}
+
+	var denied *kube.DeniedError
+	if errors.As(err, &denied) && len(results) == 0 {
+		log.Printf("Policy denied ManifestApplier: %v", err)
+		return toolRefused("ManifestApplier", denied.Namespace, "PolicyDenied", fmt.Sprintf("the manifest is not allowed by the server policy: %v", err)), nil
+	}
+	if err != nil && len(results) > 0 {
+		// the objects before the failing one are applied, the caller must know them.
+		// The error is only reported in the result, so that its content reaches the client.
+		jsonDoc, _ := json.Marshal(PartialApply{Error: err.Error(), Applied: results})
+		return &protocol.CallToolResult{
+			Content: []protocol.Content{
+				&protocol.TextContent{
+					Type: "text",
+					Text: string(jsonDoc),
+				},
+			},
+			IsError: true,
+		}, nil
+	}
+	if err != nil {
+		return &protocol.CallToolResult{
+			Content: []protocol.Content{
+				&protocol.TextContent{
+					Type: "text",
+					Text: fmt.Sprintf(`{"Error":"%v"}`, err),
+				},
+			},
+			IsError: true,
+		}, err
+	}
+
+	jsonDoc, err := json.Marshal(results)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal output: %v", err)
+	}
+
+	return &protocol.CallToolResult{
+		Content: []protocol.Content{
+			&protocol.TextContent{
+				Type: "text",
+				Text: string(jsonDoc),
+			},
+		},
+		IsError: false,
+	}, nil
+}
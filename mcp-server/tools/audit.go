@@ -0,0 +1,124 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"uf/mcp/pkg/audit"
+	"uf/mcp/pkg/auth"
+	"uf/mcp/pkg/kube"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+)
+
+var (
+	auditLog *audit.Logger
+)
+
+// SetAuditLog sets the logger receiving a record for every tool call, nil disables auditing
+func SetAuditLog(logger *audit.Logger) {
+	auditLog = logger
+}
+
+// WithAudit wraps a tool handler so that every call, including refused ones, is written
+// to the audit log with the caller identity, outcome and duration
+func WithAudit(tool *protocol.Tool, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+		if auditLog == nil {
+			return handler(ctx, req)
+		}
+
+		start := time.Now()
+		result, err := handler(ctx, req)
+
+		args := map[string]any{}
+		if len(req.RawArguments) > 0 {
+			json.Unmarshal(req.RawArguments, &args)
+		}
+
+		record := audit.Record{
+			Timestamp:  start.UTC(),
+			Source:     "mcp-server",
+			Identity:   auth.IdentityFromContext(ctx),
+			Tool:       tool.Name,
+			Arguments:  audit.Arguments(args),
+			Outcome:    audit.OutcomeSuccess,
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		switch {
+		case err != nil:
+			record.Outcome, record.Error = audit.OutcomeError, err.Error()
+		case result != nil && result.IsError:
+			record.Outcome, record.Error = resultError(result)
+		}
+		if IsMutating(tool.Name) {
+			record.SetObjects(args)
+		}
+		// a failed manifest may still have applied the objects before the failing one
+		if tool.Name == "ManifestApplier" && result != nil {
+			record.Objects = appliedObjects(result)
+		}
+
+		if err := auditLog.Write(record); err != nil {
+			log.Printf("Failed to write audit record: %v", err)
+		}
+		return result, err
+	}
+}
+
+// resultError returns the outcome and message of a failed tool result, calls refused
+// by the policy or read-only mode carry an error code
+func resultError(result *protocol.CallToolResult) (string, string) {
+	for _, content := range result.Content {
+		text, ok := content.(*protocol.TextContent)
+		if !ok {
+			continue
+		}
+
+		var doc PolicyError
+		if err := json.Unmarshal([]byte(text.Text), &doc); err == nil && doc.Error != "" {
+			if doc.Code != "" {
+				return audit.OutcomeDenied, doc.Error
+			}
+			return audit.OutcomeError, doc.Error
+		}
+		return audit.OutcomeError, text.Text
+	}
+	return audit.OutcomeError, ""
+}
+
+// appliedObjects lists the objects of a manifest that were created or changed, including
+// those applied before a failing object
+func appliedObjects(result *protocol.CallToolResult) []string {
+	var objects []string
+	for _, content := range result.Content {
+		text, ok := content.(*protocol.TextContent)
+		if !ok {
+			continue
+		}
+
+		var results []kube.ApplyResult
+		if err := json.Unmarshal([]byte(text.Text), &results); err != nil {
+			var partial PartialApply
+			if err := json.Unmarshal([]byte(text.Text), &partial); err != nil {
+				continue
+			}
+			results = partial.Applied
+		}
+		for _, r := range results {
+			if r.DryRun || r.Action == "unchanged" {
+				continue
+			}
+			name := r.Name
+			if r.Namespace != "" {
+				name = r.Namespace + "/" + r.Name
+			}
+			objects = append(objects, fmt.Sprintf("%s %s (%s)", r.Kind, name, r.Action))
+		}
+	}
+	return objects
+}
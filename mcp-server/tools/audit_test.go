@@ -0,0 +1,218 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"uf/mcp/pkg/audit"
+	"uf/mcp/pkg/auth"
+	"uf/mcp/pkg/kube"
+
+	"github.com/ThinkInAIXYZ/go-mcp/client"
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+	"github.com/ThinkInAIXYZ/go-mcp/transport"
+)
+
+// setupAuditLog writes the audit records of the test to a temporary file
+func setupAuditLog(t *testing.T) *audit.Logger {
+	t.Helper()
+
+	logger, err := audit.NewLogger(filepath.Join(t.TempDir(), "audit.jsonl"), 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetAuditLog(logger)
+	t.Cleanup(func() { SetAuditLog(nil) })
+	return logger
+}
+
+func auditRecords(t *testing.T, logger *audit.Logger) []audit.Record {
+	t.Helper()
+
+	records, err := logger.Query(audit.Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return records
+}
+
+func TestWithAudit(t *testing.T) {
+	applied, _ := json.Marshal(PartialApply{
+		Error: "ConfigMap 'b': server-side apply failed",
+		Applied: []kube.ApplyResult{
+			{Kind: "ConfigMap", Name: "a", Namespace: "shop", Action: "created"},
+			{Kind: "ConfigMap", Name: "unchanged", Namespace: "shop", Action: "unchanged"},
+		},
+	})
+
+	tests := []struct {
+		name     string
+		tool     string
+		args     string
+		result   *protocol.CallToolResult
+		err      error
+		identity string
+		outcome  string
+		target   []string
+		objects  []string
+	}{
+		{
+			name:     "read-only tool",
+			tool:     "ObjectDescriber",
+			args:     `{"kind":"deploy","name":"cart","namespace":"shop"}`,
+			result:   textResult(`{"kind":"Deployment"}`, false),
+			identity: "chat-client",
+			outcome:  audit.OutcomeSuccess,
+		},
+		{
+			name:     "mutating tool",
+			tool:     "ServiceRestarter",
+			args:     `{"service":"cart","namespace":"shop"}`,
+			result:   textResult(`{"message":"Restart initiated"}`, false),
+			identity: "cert:ops",
+			outcome:  audit.OutcomeSuccess,
+			target:   []string{"Deployment shop/cart"},
+			objects:  []string{"Deployment shop/cart"},
+		},
+		{
+			name:     "dry run",
+			tool:     "NodeDrain",
+			args:     `{"node":"worker-1","dryRun":true}`,
+			result:   textResult(`{"node":"worker-1","dryRun":true}`, false),
+			identity: "cert:ops",
+			outcome:  audit.OutcomeSuccess,
+			target:   []string{"Node worker-1"},
+		},
+		{
+			name:     "refused by the policy",
+			tool:     "ServiceRestarter",
+			args:     `{"service":"dns","namespace":"kube-system"}`,
+			result:   toolRefused("ServiceRestarter", "kube-system", "PolicyDenied", "denied"),
+			identity: "chat-client",
+			outcome:  audit.OutcomeDenied,
+			target:   []string{"Deployment kube-system/dns"},
+		},
+		{
+			name:     "handler error",
+			tool:     "NodeCordon",
+			args:     `{"node":"worker-1","cordon":true}`,
+			err:      fmt.Errorf("failed to patch node"),
+			identity: "chat-client",
+			outcome:  audit.OutcomeError,
+			target:   []string{"Node worker-1"},
+		},
+		{
+			name:     "partially applied manifest",
+			tool:     "ManifestApplier",
+			args:     `{"manifest":"kind: ConfigMap","apply":true}`,
+			result:   textResult(string(applied), true),
+			identity: "chat-client",
+			outcome:  audit.OutcomeError,
+			objects:  []string{"ConfigMap shop/a (created)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := setupAuditLog(t)
+
+			handler := func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+				return tt.result, tt.err
+			}
+			ctx := auth.WithIdentity(context.Background(), tt.identity)
+			req := &protocol.CallToolRequest{Name: tt.tool, RawArguments: json.RawMessage(tt.args)}
+			WithAudit(&protocol.Tool{Name: tt.tool}, handler)(ctx, req)
+
+			records := auditRecords(t, logger)
+			if len(records) != 1 {
+				t.Fatalf("got %d audit records, want 1", len(records))
+			}
+			r := records[0]
+			if r.Identity != tt.identity || r.Tool != tt.tool || r.Outcome != tt.outcome {
+				t.Errorf("record = %s %s %s, want %s %s %s", r.Identity, r.Tool, r.Outcome, tt.identity, tt.tool, tt.outcome)
+			}
+			if fmt.Sprint(r.Target) != fmt.Sprint(tt.target) {
+				t.Errorf("target = %v, want %v", r.Target, tt.target)
+			}
+			if fmt.Sprint(r.Objects) != fmt.Sprint(tt.objects) {
+				t.Errorf("objects = %v, want %v", r.Objects, tt.objects)
+			}
+		})
+	}
+}
+
+// bearerTransport authenticates the requests of the MCP client
+type bearerTransport struct {
+	token string
+}
+
+func (b bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+b.token)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// TestAuditIdentityOverHTTP calls a tool through the streamable HTTP transport, as
+// mcp-server serves it, to prove that the identity stored in the request context by the
+// authentication middleware reaches the tool handlers and the audit record
+func TestAuditIdentityOverHTTP(t *testing.T) {
+	logger := setupAuditLog(t)
+
+	tokenFile := filepath.Join(t.TempDir(), "tokens.yaml")
+	if err := os.WriteFile(tokenFile, []byte("tokens:\n  - identity: chat-client\n    token: test-token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	authenticator, err := auth.NewAuthenticator(tokenFile, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	streamableTransport, handler, err := transport.NewStreamableHTTPServerTransportAndHandler()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mcpServer, err := server.NewServer(streamableTransport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tool, toolHandler := GetCalculatorTool()
+	mcpServer.RegisterTool(tool, WithAudit(tool, WithPolicy(tool, toolHandler)))
+	go mcpServer.Run()
+	defer mcpServer.Shutdown(context.Background())
+
+	httpServer := httptest.NewServer(authenticator.Middleware(handler.HandleMCP()))
+	defer httpServer.Close()
+
+	clientTransport, err := transport.NewStreamableHTTPClientTransport(
+		httpServer.URL,
+		transport.WithStreamableHTTPClientOptionHTTPClient(&http.Client{Transport: bearerTransport{token: "test-token"}}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mcpClient, err := client.NewClient(clientTransport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mcpClient.Close()
+
+	request := protocol.NewCallToolRequest("Calculator", map[string]any{"operation": "add", "a": 1, "b": 2})
+	if _, err := mcpClient.CallTool(context.Background(), request); err != nil {
+		t.Fatal(err)
+	}
+
+	records := auditRecords(t, logger)
+	if len(records) != 1 {
+		t.Fatalf("got %d audit records, want 1", len(records))
+	}
+	if records[0].Identity != "chat-client" {
+		t.Errorf("identity = %q, want chat-client", records[0].Identity)
+	}
+}
@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"uf/mcp/pkg/kube"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+)
+
+type NodeCordon struct {
+	Node     string `json:"node" description:"Name of the Node to cordon or uncordon" required:"true"`
+	Uncordon bool   `json:"uncordon" description:"Set to true to mark the node schedulable again (uncordon). Defaults to false (cordon)"`
+}
+
+// Name of the tool
+func (n *NodeCordon) Name() string {
+	return "NodeCordon"
+}
+
+// Description of the tool
+func (n *NodeCordon) Description() string {
+	desc := []string{
+		"Tool to cordon a Kubernetes Node, marking it unschedulable so that no new pods are placed on it.",
+		"With uncordon set to true the node is marked schedulable again.",
+		"This tool modifies the cluster.",
+	}
+	return strings.Join(desc, "\n")
+}
+
+func GetNodeCordonTool() (*protocol.Tool, server.ToolHandlerFunc) {
+	log.Print("Initializing NodeCordon tool")
+
+	toolStruct := NodeCordon{}
+
+	tool, err := protocol.NewTool(
+		toolStruct.Name(),
+		toolStruct.Description(),
+		toolStruct,
+	)
+	if err != nil {
+		log.Fatalf("Failed to create tool: %v", err)
+	}
+
+	return tool, handleNodeCordon
+}
+
+// Tool execution logic
+func handleNodeCordon(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+	var request NodeCordon
+
+	if err := protocol.VerifyAndUnmarshal(req.RawArguments, &request); err != nil {
+		return nil, err
+	}
+
+	output, err := kube.CordonNode(request.Node, !request.Uncordon)
+	if err != nil {
+		return &protocol.CallToolResult{
+			Content: []protocol.Content{
+				&protocol.TextContent{
+					Type: "text",
+					Text: fmt.Sprintf(`{"Error":"%v"}`, err),
+				},
+			},
+			IsError: true,
+		}, err
+	}
+
+	jsonDoc, err := json.Marshal(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal output: %v", err)
+	}
+
+	return &protocol.CallToolResult{
+		Content: []protocol.Content{
+			&protocol.TextContent{
+				Type: "text",
+				Text: string(jsonDoc),
+			},
+		},
+		IsError: false,
+	}, nil
+}
@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"uf/mcp/pkg/kube"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+)
+
+type ObjectDescriber struct {
+	Namespace string `json:"namespace" description:"Namespace of the object. Ignored for cluster scoped kinds such as Node or Namespace"`
+	Kind      string `json:"kind" description:"Kind of the object, e.g. Deployment, Service, Ingress, Pod, ConfigMap, Node" required:"true"`
+	Name      string `json:"name" description:"Name of the object to describe" required:"true"`
+}
+
+// Name of the tool
+func (o *ObjectDescriber) Name() string {
+	return "ObjectDescriber"
+}
+
+// Description of the tool
+func (o *ObjectDescriber) Description() string {
+	desc := []string{
+		"Tool to describe a single Kubernetes object of any kind, similar to 'kubectl describe'.",
+		"Returns spec highlights, containers, status, conditions, owner references and recent events of the object.",
+		"Works with built-in kinds as well as custom resources installed in the cluster.",
+	}
+	return strings.Join(desc, "\n")
+}
+
+func GetObjectDescriberTool() (*protocol.Tool, server.ToolHandlerFunc) {
+	log.Print("Initializing ObjectDescriber tool")
+
+	toolStruct := ObjectDescriber{}
+
+	tool, err := protocol.NewTool(
+		toolStruct.Name(),
+		toolStruct.Description(),
+		toolStruct,
+	)
+	if err != nil {
+		log.Fatalf("Failed to create tool: %v", err)
+	}
+
+	return tool, handleObjectDescriber
+}
+
+// Tool execution logic
+func handleObjectDescriber(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+	var request ObjectDescriber
+
+	if err := protocol.VerifyAndUnmarshal(req.RawArguments, &request); err != nil {
+		return nil, err
+	}
+
+	description, err := kube.DescribeObject(request.Namespace, request.Kind, request.Name)
+	if err != nil {
+		return &protocol.CallToolResult{
+			Content: []protocol.Content{
+				&protocol.TextContent{
+					Type: "text",
+					Text: fmt.Sprintf(`{"Error":"%v"}`, err),
+				},
+			},
+			IsError: true,
+		}, err
+	}
+
+	jsonDoc, err := json.Marshal(description)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal output: %v", err)
+	}
+
+	return &protocol.CallToolResult{
+		Content: []protocol.Content{
+			&protocol.TextContent{
+				Type: "text",
+				Text: string(jsonDoc),
+			},
+		},
+		IsError: false,
+	}, nil
+}
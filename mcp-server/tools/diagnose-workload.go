@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"uf/mcp/pkg/kube"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+)
+
+type WorkloadDiagnoser struct {
+	Namespace  string `json:"namespace" description:"Name of the Namespace where the deployment is hosted" required:"true"`
+	Deployment string `json:"deployment" description:"Name of the Deployment (application) to diagnose" required:"true"`
+}
+
+// Name of the tool
+func (w *WorkloadDiagnoser) Name() string {
+	return "WorkloadDiagnoser"
+}
+
+// Description of the tool
+func (w *WorkloadDiagnoser) Description() string {
+	desc := []string{
+		"Tool to diagnose why an application (Deployment) is broken, e.g. CrashLoopBackOff, ImagePullBackOff, OOMKilled or Pending pods.",
+		"Inspects the deployment's pods, init container and container states, last termination states, recent events, the logs of failing containers and resource usage.",
+		"Returns a list of findings, each with a probable cause, the supporting evidence and a suggested action.",
+	}
+	return strings.Join(desc, "\n")
+}
+
+func GetWorkloadDiagnoserTool() (*protocol.Tool, server.ToolHandlerFunc) {
+	log.Print("Initializing WorkloadDiagnoser tool")
+
+	toolStruct := WorkloadDiagnoser{}
+
+	tool, err := protocol.NewTool(
+		toolStruct.Name(),
+		toolStruct.Description(),
+		toolStruct,
+	)
+	if err != nil {
+		log.Fatalf("Failed to create tool: %v", err)
+	}
+
+	return tool, handleWorkloadDiagnoser
+}
+
+// Tool execution logic
+func handleWorkloadDiagnoser(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+	var request WorkloadDiagnoser
+
+	if err := protocol.VerifyAndUnmarshal(req.RawArguments, &request); err != nil {
+		return nil, err
+	}
+
+	diagnosis, err := kube.DiagnoseWorkload(request.Namespace, request.Deployment)
+	if err != nil {
+		return &protocol.CallToolResult{
+			Content: []protocol.Content{
+				&protocol.TextContent{
+					Type: "text",
+					Text: fmt.Sprintf(`{"Error":"%v"}`, err),
+				},
+			},
+			IsError: true,
+		}, err
+	}
+
+	jsonDoc, err := json.Marshal(diagnosis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal output: %v", err)
+	}
+
+	return &protocol.CallToolResult{
+		Content: []protocol.Content{
+			&protocol.TextContent{
+				Type: "text",
+				Text: string(jsonDoc),
+			},
+		},
+		IsError: false,
+	}, nil
+}
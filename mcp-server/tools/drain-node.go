@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"uf/mcp/pkg/kube"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+)
+
+type NodeDrain struct {
+	Node           string `json:"node" description:"Name of the Node to drain" required:"true"`
+	DryRun         bool   `json:"dryRun" description:"Set to true to only list the pods that would be evicted, without changing the cluster"`
+	TimeoutSeconds int    `json:"timeoutSeconds" description:"Optional time limit for the drain in seconds. Defaults to 300"`
+}
+
+// Name of the tool
+func (n *NodeDrain) Name() string {
+	return "NodeDrain"
+}
+
+// Description of the tool
+func (n *NodeDrain) Description() string {
+	desc := []string{
+		"Tool to drain a Kubernetes Node for maintenance: the node is cordoned and its pods are evicted using the Eviction API.",
+		"PodDisruptionBudgets are respected, DaemonSet pods, mirror pods and pods without a controller are skipped.",
+		"Pods whose eviction or termination did not finish before the timeout are reported as pending, pods in namespaces denied by the server policy are skipped.",
+		"Use dryRun to list what would be evicted and which evictions are currently blocked by a PodDisruptionBudget.",
+		"This tool modifies the cluster unless dryRun is set.",
+	}
+	return strings.Join(desc, "\n")
+}
+
+func GetNodeDrainTool() (*protocol.Tool, server.ToolHandlerFunc) {
+	log.Print("Initializing NodeDrain tool")
+
+	toolStruct := NodeDrain{}
+
+	tool, err := protocol.NewTool(
+		toolStruct.Name(),
+		toolStruct.Description(),
+		toolStruct,
+	)
+	if err != nil {
+		log.Fatalf("Failed to create tool: %v", err)
+	}
+
+	return tool, handleNodeDrain
+}
+
+// Tool execution logic
+func handleNodeDrain(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+	var request NodeDrain
+
+	if err := protocol.VerifyAndUnmarshal(req.RawArguments, &request); err != nil {
+		return nil, err
+	}
+
+	if readOnly && !request.DryRun {
+		return refuseReadOnly("NodeDrain", "only a dry run is available, dryRun must be true"), nil
+	}
+
+	timeout := time.Duration(request.TimeoutSeconds) * time.Second
+	report, err := kube.DrainNode(request.Node, request.DryRun, timeout, namespaceCheck("NodeDrain"), progressReporter(ctx, req))
+	if err != nil {
+		return &protocol.CallToolResult{
+			Content: []protocol.Content{
+				&protocol.TextContent{
+					Type: "text",
+					Text: fmt.Sprintf(`{"Error":"%v"}`, err),
+				},
+			},
+			IsError: true,
+		}, err
+	}
+
+	jsonDoc, err := json.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal output: %v", err)
+	}
+
+	return &protocol.CallToolResult{
+		Content: []protocol.Content{
+			&protocol.TextContent{
+				Type: "text",
+				Text: string(jsonDoc),
+			},
+		},
+		IsError: false,
+	}, nil
+}
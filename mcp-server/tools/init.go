@@ -1,15 +1,50 @@
 package tools
 
 import (
-	"uf/mcp/pkg/common"
+	"fmt"
+	"log"
 
-	"github.com/tmc/langchaingo/llms/openai"
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
 )
 
 var (
-	model *openai.LLM
+	// tools that change the state of the cluster
+	mutatingTools = map[string]bool{
+		"ServiceRestarter": true,
+		"CronJobTrigger":   true,
+		"NodeCordon":       true,
+		"NodeDrain":        true,
+		"ManifestApplier":  true,
+	}
+
+	// mutating tools with a dry run, which stays available in read-only mode. Their handlers
+	// refuse the calls changing the cluster themselves.
+	dryRunTools = map[string]bool{
+		"NodeDrain":       true,
+		"ManifestApplier": true,
+	}
+
+	// mutating tools are refused even if they got registered
+	readOnly bool
 )
 
-func init() {
-	model = common.GetModel()
+// SetReadOnly enables or disables the execution of mutating tools
+func SetReadOnly(enabled bool) {
+	readOnly = enabled
+}
+
+// IsMutating reports whether a tool changes the state of the cluster
+func IsMutating(toolName string) bool {
+	return mutatingTools[toolName]
+}
+
+// HasDryRun reports whether a mutating tool can be called without changing the cluster
+func HasDryRun(toolName string) bool {
+	return dryRunTools[toolName]
+}
+
+// refuseReadOnly is the result of a call changing the cluster in read-only mode
+func refuseReadOnly(toolName, reason string) *protocol.CallToolResult {
+	log.Printf("Read-only mode, refusing to execute %s", toolName)
+	return toolRefused(toolName, "", "ReadOnly", fmt.Sprintf("%s and the server runs in read-only mode", reason))
 }
@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"uf/mcp/pkg/kube"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+)
+
+type ServiceInspector struct {
+	Namespace string `json:"namespace" description:"Name of the Namespace where the service is hosted" required:"true"`
+	Service   string `json:"service" description:"Optional name of the Service. All Services of the namespace are inspected when omitted"`
+}
+
+// Name of the tool
+func (s *ServiceInspector) Name() string {
+	return "ServiceInspector"
+}
+
+// Description of the tool
+func (s *ServiceInspector) Description() string {
+	desc := []string{
+		"Tool to inspect the routing and endpoint health of Kubernetes Services.",
+		"Returns type, ports, selector, cluster and external IPs, and the ready vs. not-ready endpoints with their backing pods.",
+		"Flags services whose selector matches no pods or which have no ready endpoints.",
+	}
+	return strings.Join(desc, "\n")
+}
+
+func GetServiceInspectorTool() (*protocol.Tool, server.ToolHandlerFunc) {
+	log.Print("Initializing ServiceInspector tool")
+
+	toolStruct := ServiceInspector{}
+
+	tool, err := protocol.NewTool(
+		toolStruct.Name(),
+		toolStruct.Description(),
+		toolStruct,
+	)
+	if err != nil {
+		log.Fatalf("Failed to create tool: %v", err)
+	}
+
+	return tool, handleServiceInspector
+}
+
+// Tool execution logic
+func handleServiceInspector(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+	var request ServiceInspector
+
+	if err := protocol.VerifyAndUnmarshal(req.RawArguments, &request); err != nil {
+		return nil, err
+	}
+
+	services, err := kube.InspectServices(request.Namespace, request.Service)
+	if err != nil {
+		return &protocol.CallToolResult{
+			Content: []protocol.Content{
+				&protocol.TextContent{
+					Type: "text",
+					Text: fmt.Sprintf(`{"Error":"%v"}`, err),
+				},
+			},
+			IsError: true,
+		}, err
+	}
+
+	jsonDoc, err := json.Marshal(services)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal output: %v", err)
+	}
+
+	return &protocol.CallToolResult{
+		Content: []protocol.Content{
+			&protocol.TextContent{
+				Type: "text",
+				Text: string(jsonDoc),
+			},
+		},
+		IsError: false,
+	}, nil
+}
@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"uf/mcp/pkg/kube"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+)
+
+type CronJobFinder struct {
+	Namespace string `json:"namespace" description:"Name of the Namespace for which the list of cronjobs are requested" required:"true"`
+}
+
+// Name of the tool
+func (c *CronJobFinder) Name() string {
+	return "CronJobFinder"
+}
+
+// Description of the tool
+func (c *CronJobFinder) Description() string {
+	desc := []string{
+		"Tool to find the list of CronJobs in a given Kubernetes Namespace.",
+		"Returns the schedule, suspend state, active jobs, and last schedule and last successful time of each CronJob.",
+	}
+	return strings.Join(desc, "\n")
+}
+
+func GetCronJobFinderTool() (*protocol.Tool, server.ToolHandlerFunc) {
+	log.Print("Initializing CronJobFinder tool")
+
+	toolStruct := CronJobFinder{}
+
+	tool, err := protocol.NewTool(
+		toolStruct.Name(),
+		toolStruct.Description(),
+		toolStruct,
+	)
+	if err != nil {
+		log.Fatalf("Failed to create tool: %v", err)
+	}
+
+	return tool, handleCronJobFinder
+}
+
+// Tool execution logic
+func handleCronJobFinder(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+	var request CronJobFinder
+
+	if err := protocol.VerifyAndUnmarshal(req.RawArguments, &request); err != nil {
+		return nil, err
+	}
+
+	cronJobs, err := kube.GetCronJobs(request.Namespace)
+	if err != nil {
+		return &protocol.CallToolResult{
+			Content: []protocol.Content{
+				&protocol.TextContent{
+					Type: "text",
+					Text: fmt.Sprintf(`{"Error":"%v"}`, err),
+				},
+			},
+			IsError: true,
+		}, err
+	}
+
+	jsonDoc, err := json.Marshal(cronJobs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal output: %v", err)
+	}
+
+	return &protocol.CallToolResult{
+		Content: []protocol.Content{
+			&protocol.TextContent{
+				Type: "text",
+				Text: string(jsonDoc),
+			},
+		},
+		IsError: false,
+	}, nil
+}
@@ -0,0 +1,139 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"uf/mcp/pkg/kube"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+)
+
+type ImageInventory struct {
+	Namespace         string `json:"namespace" description:"Optional Namespace to inspect. All namespaces are inspected when omitted"`
+	AllowedRegistries string `json:"allowedRegistries" description:"Optional comma separated list of allowed registries, e.g. docker.io,quay.io. Defaults to the ALLOWED_REGISTRIES environment variable of the server, and can only narrow that list"`
+}
+
+// Name of the tool
+func (i *ImageInventory) Name() string {
+	return "ImageInventory"
+}
+
+// Description of the tool
+func (i *ImageInventory) Description() string {
+	desc := []string{
+		"Tool to list every container image used by the workloads of a Kubernetes Namespace or of the whole cluster.",
+		"Images are reported with their registry, repository, tag, running digests and the workloads using them, grouped by registry and by tag.",
+		"Flags images using the :latest tag, pods running a different digest than their siblings, and images from registries that are not allowed.",
+	}
+	return strings.Join(desc, "\n")
+}
+
+func GetImageInventoryTool() (*protocol.Tool, server.ToolHandlerFunc) {
+	log.Print("Initializing ImageInventory tool")
+
+	toolStruct := ImageInventory{}
+
+	tool, err := protocol.NewTool(
+		toolStruct.Name(),
+		toolStruct.Description(),
+		toolStruct,
+	)
+	if err != nil {
+		log.Fatalf("Failed to create tool: %v", err)
+	}
+
+	return tool, handleImageInventory
+}
+
+// Tool execution logic
+func handleImageInventory(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+	var request ImageInventory
+
+	if err := protocol.VerifyAndUnmarshal(req.RawArguments, &request); err != nil {
+		return nil, err
+	}
+
+	registries, err := allowedRegistries(os.Getenv("ALLOWED_REGISTRIES"), request.AllowedRegistries)
+	if err != nil {
+		return &protocol.CallToolResult{
+			Content: []protocol.Content{
+				&protocol.TextContent{
+					Type: "text",
+					Text: fmt.Sprintf(`{"Error":"%v"}`, err),
+				},
+			},
+			IsError: true,
+		}, err
+	}
+
+	report, err := kube.GetImageInventory(request.Namespace, registries)
+	if err != nil {
+		return &protocol.CallToolResult{
+			Content: []protocol.Content{
+				&protocol.TextContent{
+					Type: "text",
+					Text: fmt.Sprintf(`{"Error":"%v"}`, err),
+				},
+			},
+			IsError: true,
+		}, err
+	}
+
+	jsonDoc, err := json.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal output: %v", err)
+	}
+
+	return &protocol.CallToolResult{
+		Content: []protocol.Content{
+			&protocol.TextContent{
+				Type: "text",
+				Text: string(jsonDoc),
+			},
+		},
+		IsError: false,
+	}, nil
+}
+
+// allowedRegistries returns the registries images are checked against. The server list
+// is authoritative, the requested registries can only narrow it down.
+func allowedRegistries(server, requested string) ([]string, error) {
+	serverList := splitList(server)
+	requestedList := splitList(requested)
+	if len(requestedList) == 0 {
+		return serverList, nil
+	}
+	if len(serverList) == 0 {
+		return requestedList, nil
+	}
+
+	var registries []string
+	for _, r := range requestedList {
+		for _, allowed := range serverList {
+			if strings.EqualFold(r, allowed) {
+				registries = append(registries, r)
+				break
+			}
+		}
+	}
+	if len(registries) == 0 {
+		return nil, fmt.Errorf("none of the registries %s is allowed by the server, allowed registries: %s", requested, server)
+	}
+	return registries, nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -2,6 +2,7 @@ package tools
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"strings"
@@ -25,6 +26,7 @@ func (i *IngressFinder) Name() string {
 func (i *IngressFinder) Description() string {
 	desc := []string{
 		"Tool to list all Ingresses in a given Kubernetes Namespace.",
+		"Returns the hosts, paths and backend services of each Ingress, its ingress class and the TLS secrets it references.",
 		"Compatible with Minikube and other Kubernetes clusters.",
 	}
 	return strings.Join(desc, "\n")
@@ -68,11 +70,16 @@ func handleIngressFinder(ctx context.Context, req *protocol.CallToolRequest) (*p
 		}, err
 	}
 
+	jsonDoc, err := json.Marshal(ingresses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal output: %v", err)
+	}
+
 	return &protocol.CallToolResult{
 		Content: []protocol.Content{
 			&protocol.TextContent{
 				Type: "text",
-				Text: fmt.Sprintf("Ingresses in namespace '%s':\n%s", request.Namespace, strings.Join(ingresses, "\n")),
+				Text: fmt.Sprintf("Ingresses in namespace '%s':\n%s", request.Namespace, string(jsonDoc)),
 			},
 		},
 		IsError: false,
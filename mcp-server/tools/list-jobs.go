@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"uf/mcp/pkg/kube"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+)
+
+type JobFinder struct {
+	Namespace string `json:"namespace" description:"Name of the Namespace for which the list of jobs are requested" required:"true"`
+}
+
+// Name of the tool
+func (j *JobFinder) Name() string {
+	return "JobFinder"
+}
+
+// Description of the tool
+func (j *JobFinder) Description() string {
+	desc := []string{
+		"Tool to find the list of Jobs in a given Kubernetes Namespace.",
+		"Returns completions, active/succeeded/failed pod counts, status, start and completion time, and the owning CronJob of each Job.",
+	}
+	return strings.Join(desc, "\n")
+}
+
+func GetJobFinderTool() (*protocol.Tool, server.ToolHandlerFunc) {
+	log.Print("Initializing JobFinder tool")
+
+	toolStruct := JobFinder{}
+
+	tool, err := protocol.NewTool(
+		toolStruct.Name(),
+		toolStruct.Description(),
+		toolStruct,
+	)
+	if err != nil {
+		log.Fatalf("Failed to create tool: %v", err)
+	}
+
+	return tool, handleJobFinder
+}
+
+// Tool execution logic
+func handleJobFinder(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+	var request JobFinder
+
+	if err := protocol.VerifyAndUnmarshal(req.RawArguments, &request); err != nil {
+		return nil, err
+	}
+
+	jobs, err := kube.GetJobs(request.Namespace)
+	if err != nil {
+		return &protocol.CallToolResult{
+			Content: []protocol.Content{
+				&protocol.TextContent{
+					Type: "text",
+					Text: fmt.Sprintf(`{"Error":"%v"}`, err),
+				},
+			},
+			IsError: true,
+		}, err
+	}
+
+	jsonDoc, err := json.Marshal(jobs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal output: %v", err)
+	}
+
+	return &protocol.CallToolResult{
+		Content: []protocol.Content{
+			&protocol.TextContent{
+				Type: "text",
+				Text: string(jsonDoc),
+			},
+		},
+		IsError: false,
+	}, nil
+}
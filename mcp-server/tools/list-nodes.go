@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"uf/mcp/pkg/kube"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+)
+
+type NodeFinder struct{}
+
+// Name of the tool
+func (n *NodeFinder) Name() string {
+	return "NodeFinder"
+}
+
+// Description of the tool
+func (n *NodeFinder) Description() string {
+	desc := []string{
+		"Tool to list all nodes in the Kubernetes cluster.",
+		"Returns node conditions, taints, kubelet version, and allocatable vs. requested CPU and memory for each node.",
+		"Useful for finding nodes under memory, disk or PID pressure, or nodes that are over committed.",
+	}
+	return strings.Join(desc, "\n")
+}
+
+func GetNodeFinderTool() (*protocol.Tool, server.ToolHandlerFunc) {
+	log.Print("Initializing NodeFinder tool")
+
+	toolStruct := NodeFinder{}
+
+	tool, err := protocol.NewTool(
+		toolStruct.Name(),
+		toolStruct.Description(),
+		toolStruct,
+	)
+	if err != nil {
+		log.Fatalf("Failed to create tool: %v", err)
+	}
+
+	return tool, handleNodeFinder
+}
+
+// Tool execution logic
+func handleNodeFinder(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+	var request NodeFinder
+
+	if err := protocol.VerifyAndUnmarshal(req.RawArguments, &request); err != nil {
+		return nil, err
+	}
+
+	nodes, err := kube.GetNodes()
+	if err != nil {
+		return &protocol.CallToolResult{
+			Content: []protocol.Content{
+				&protocol.TextContent{
+					Type: "text",
+					Text: fmt.Sprintf(`{"Error":"%v"}`, err),
+				},
+			},
+			IsError: true,
+		}, err
+	}
+
+	jsonDoc, err := json.Marshal(nodes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal output: %v", err)
+	}
+
+	return &protocol.CallToolResult{
+		Content: []protocol.Content{
+			&protocol.TextContent{
+				Type: "text",
+				Text: string(jsonDoc),
+			},
+		},
+		IsError: false,
+	}, nil
+}
@@ -2,6 +2,7 @@ package tools
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"strings"
@@ -13,7 +14,9 @@ import (
 )
 
 type PodFinder struct {
-	Namespace string `json:"namespace" description:"Name of the Namespace for which the list of pods are requested" required:"true"`
+	Namespace     string `json:"namespace" description:"Name of the Namespace for which the list of pods are requested" required:"true"`
+	LabelSelector string `json:"labelSelector" description:"Optional label selector to filter pods, e.g. app=cart,tier=backend"`
+	FieldSelector string `json:"fieldSelector" description:"Optional field selector to filter pods, e.g. status.phase=Running or spec.nodeName=minikube"`
 }
 
 // Name of the tool
@@ -25,6 +28,8 @@ func (p *PodFinder) Name() string {
 func (p *PodFinder) Description() string {
 	desc := []string{
 		"Tool to find the list of pods in a given Kubernetes Namespace.",
+		"Returns phase, ready containers, restart count, node, age and last termination reason of each pod.",
+		"Pods can be filtered with an optional label selector and field selector.",
 		"Useful for inspecting workload status and debugging cluster activity.",
 	}
 	return strings.Join(desc, "\n")
@@ -55,7 +60,7 @@ func handlePodFinder(ctx context.Context, req *protocol.CallToolRequest) (*proto
 		return nil, err
 	}
 
-	pods, err := kube.GetPods(request.Namespace)
+	pods, err := kube.GetPods(request.Namespace, request.LabelSelector, request.FieldSelector)
 	if err != nil {
 		return &protocol.CallToolResult{
 			Content: []protocol.Content{
@@ -68,11 +73,16 @@ func handlePodFinder(ctx context.Context, req *protocol.CallToolRequest) (*proto
 		}, err
 	}
 
+	jsonDoc, err := json.Marshal(pods)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal output: %v", err)
+	}
+
 	return &protocol.CallToolResult{
 		Content: []protocol.Content{
 			&protocol.TextContent{
 				Type: "text",
-				Text: fmt.Sprintf("Pods in namespace '%s':\n%s", request.Namespace, strings.Join(pods, "\n")),
+				Text: fmt.Sprintf("Pods in namespace '%s':\n%s", request.Namespace, string(jsonDoc)),
 			},
 		},
 		IsError: false,
@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"sort"
+
+	"uf/mcp/pkg/kube"
+)
+
+var (
+	getNamespaces = kube.Permission{Verb: "get", Resource: "namespaces"}
+	listPods      = kube.Permission{Verb: "list", Resource: "pods"}
+
+	// permissions needed by each tool. Tools that are not listed need none.
+	toolPermissions = map[string][]kube.Permission{
+		"ServiceFinder":      {getNamespaces, {Verb: "list", Resource: "services"}},
+		"DeploymentFinder":   {getNamespaces, {Verb: "list", Group: "apps", Resource: "deployments"}},
+		"NamespaceFinder":    {{Verb: "list", Resource: "namespaces"}},
+		"IngressFinder":      {getNamespaces, {Verb: "list", Group: "networking.k8s.io", Resource: "ingresses"}},
+		"ServiceRestarter":   {listPods, {Verb: "patch", Group: "apps", Resource: "deployments"}},
+		"PodCpuMemoryViewer": {listPods, {Verb: "list", Group: "metrics.k8s.io", Resource: "pods"}},
+		"PodFinder":          {getNamespaces, listPods},
+		"ObjectDescriber":    {{Verb: "list", Resource: "events"}},
+		"WorkloadDiagnoser": {
+			{Verb: "get", Group: "apps", Resource: "deployments"},
+			listPods,
+			{Verb: "list", Resource: "events"},
+			{Verb: "get", Resource: "pods", Subresource: "log"},
+		},
+		"NodeFinder":        {{Verb: "list", Resource: "nodes"}, listPods},
+		"NodeMetricsViewer": {{Verb: "list", Resource: "nodes"}, {Verb: "list", Group: "metrics.k8s.io", Resource: "nodes"}},
+		"QuotaViewer":       {getNamespaces, {Verb: "list", Resource: "resourcequotas"}, {Verb: "list", Resource: "limitranges"}},
+		"ConfigMapViewer":   {{Verb: "list", Resource: "configmaps"}, {Verb: "get", Resource: "configmaps"}},
+		"SecretViewer":      {{Verb: "list", Resource: "secrets"}, {Verb: "get", Resource: "secrets"}},
+		"CertExpiryScanner": {{Verb: "list", Group: "networking.k8s.io", Resource: "ingresses"}, {Verb: "get", Resource: "secrets"}},
+		"ServiceInspector":  {{Verb: "list", Resource: "services"}, {Verb: "get", Resource: "services"}, listPods, {Verb: "list", Group: "discovery.k8s.io", Resource: "endpointslices"}},
+		"JobFinder":         {getNamespaces, {Verb: "list", Group: "batch", Resource: "jobs"}},
+		"CronJobFinder":     {getNamespaces, {Verb: "list", Group: "batch", Resource: "cronjobs"}},
+		"CronJobTrigger":    {{Verb: "get", Group: "batch", Resource: "cronjobs"}, {Verb: "create", Group: "batch", Resource: "jobs"}},
+		"HPAViewer":         {getNamespaces, {Verb: "list", Group: "autoscaling", Resource: "horizontalpodautoscalers"}},
+		"ImageInventory":    {listPods},
+		"NodeCordon":        {{Verb: "patch", Resource: "nodes"}},
+		"NodeDrain":         {{Verb: "patch", Resource: "nodes"}, listPods, {Verb: "create", Resource: "pods", Subresource: "eviction"}},
+		"PermissionsReport": {{Verb: "create", Group: "authorization.k8s.io", Resource: "selfsubjectaccessreviews"}},
+	}
+
+	// verbs of the tools whose permissions depend on the objects of each call, e.g. the kinds
+	// of a manifest. They are not checked up front, the API error of every object is reported.
+	objectVerbs = map[string][]string{
+		"ManifestApplier": {"get", "create", "patch"},
+	}
+)
+
+type ToolPermissions struct {
+	Tool    string   `json:"tool"`
+	Allowed bool     `json:"allowed"`
+	Denied  []string `json:"denied,omitempty"`
+}
+
+// CheckToolPermissions reviews the permissions of every tool, in a namespace or
+// cluster-wide when namespace is empty
+func CheckToolPermissions(namespace string) ([]ToolPermissions, error) {
+	var names []string
+	for name := range toolPermissions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var report []ToolPermissions
+	for _, name := range names {
+		denied, err := kube.CheckPermissions(namespace, toolPermissions[name])
+		if err != nil {
+			return nil, err
+		}
+		report = append(report, toolPermissionsEntry(name, denied))
+	}
+	return report, nil
+}
+
+// CheckServerPermissions reviews the permissions of every tool cluster-wide and, for the
+// tools denied there, in namespace when it is set. A tool is only denied when both checks
+// fail, so that a service account bound by RoleBindings in its namespace keeps its tools.
+func CheckServerPermissions(namespace string) ([]ToolPermissions, error) {
+	report, err := CheckToolPermissions("")
+	if err != nil || namespace == "" {
+		return report, err
+	}
+
+	for i, entry := range report {
+		if entry.Allowed {
+			continue
+		}
+		denied, err := kube.CheckPermissions(namespace, toolPermissions[entry.Tool])
+		if err != nil {
+			return nil, err
+		}
+		report[i] = toolPermissionsEntry(entry.Tool, denied)
+	}
+	return report, nil
+}
+
+func toolPermissionsEntry(name string, denied []kube.Permission) ToolPermissions {
+	entry := ToolPermissions{Tool: name, Allowed: len(denied) == 0}
+	for _, p := range denied {
+		entry.Denied = append(entry.Denied, p.String())
+	}
+	return entry
+}
@@ -0,0 +1,251 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"strings"
+
+	"uf/mcp/pkg/kube"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+	"sigs.k8s.io/yaml"
+)
+
+// Policy restricts the namespaces tools may operate on. Every rule that applies to a
+// call must allow it. Calls without a namespace, i.e. cluster-wide calls and tools on
+// cluster-scoped objects such as NodeCordon, are denied by every rule restricting
+// namespaces unless the tool is listed in clusterScopedTools.
+//
+//	rules:
+//	  - tools: ["*"]
+//	    verbs: ["patch", "create", "delete"]
+//	    denyNamespaces: ["kube-*"]
+//	  - tools: ["ServiceRestarter"]
+//	    allowNamespaces: ["shop", "dev-*"]
+//	clusterScopedTools: ["NodeFinder", "NodeMetricsViewer"]
+type Policy struct {
+	Rules []PolicyRule `json:"rules"`
+	// tool name patterns allowed to run without a namespace whatever the rules
+	ClusterScopedTools []string `json:"clusterScopedTools"`
+}
+
+type PolicyRule struct {
+	// tool name patterns the rule applies to, all tools when empty
+	Tools []string `json:"tools"`
+	// the rule only applies to tools using one of these Kubernetes verbs, all tools when empty
+	Verbs []string `json:"verbs"`
+	// namespace patterns the tools may operate on, any namespace when empty
+	AllowNamespaces []string `json:"allowNamespaces"`
+	// namespace patterns the tools must not operate on
+	DenyNamespaces []string `json:"denyNamespaces"`
+}
+
+type PolicyError struct {
+	Error     string `json:"Error"`
+	Code      string `json:"Code"`
+	Tool      string `json:"Tool"`
+	Namespace string `json:"Namespace,omitempty"`
+}
+
+var (
+	policy *Policy
+
+	// tools checking the policy against the namespace of every object they touch
+	objectCheckedTools = map[string]bool{
+		"ManifestApplier": true,
+		"NodeDrain":       true,
+	}
+)
+
+// LoadPolicy reads the namespace policy from a YAML or JSON file
+func LoadPolicy(file string) error {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read policy file: %v", err)
+	}
+
+	p := &Policy{}
+	if err := yaml.UnmarshalStrict(content, p); err != nil {
+		return fmt.Errorf("invalid policy file '%s': %v", file, err)
+	}
+
+	for _, pattern := range p.ClusterScopedTools {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern '%s' in clusterScopedTools: %v", pattern, err)
+		}
+	}
+	for i, rule := range p.Rules {
+		for _, patterns := range [][]string{rule.Tools, rule.Verbs, rule.AllowNamespaces, rule.DenyNamespaces} {
+			for _, pattern := range patterns {
+				if _, err := path.Match(pattern, ""); err != nil {
+					return fmt.Errorf("invalid pattern '%s' in policy rule %d: %v", pattern, i+1, err)
+				}
+			}
+		}
+	}
+
+	policy = p
+	log.Printf("Loaded policy with %d rules from %s", len(p.Rules), file)
+	return nil
+}
+
+// WithPolicy wraps a tool handler so that calls denied by the policy, or mutating calls
+// in read-only mode, are rejected with a structured error before they reach the cluster
+func WithPolicy(tool *protocol.Tool, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	// tools without a namespace argument operate on cluster-scoped objects and are checked
+	// as cluster-wide calls. Tools operating on objects of several namespaces check each of
+	// them through namespaceCheck, tools not using the cluster are not checked.
+	_, namespaced := tool.InputSchema.Properties["namespace"]
+	checked := usesCluster(tool.Name) && !(namespaced && objectCheckedTools[tool.Name])
+
+	return func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+		if readOnly && IsMutating(tool.Name) && !HasDryRun(tool.Name) {
+			return refuseReadOnly(tool.Name, fmt.Sprintf("%s changes the cluster", tool.Name)), nil
+		}
+
+		if policy == nil || !checked {
+			return handler(ctx, req)
+		}
+
+		var args struct {
+			Namespace string `json:"namespace"`
+		}
+		if namespaced && len(req.RawArguments) > 0 {
+			if err := json.Unmarshal(req.RawArguments, &args); err != nil {
+				return nil, err
+			}
+		}
+
+		namespace := strings.ToLower(args.Namespace)
+		if reason := policy.check(tool.Name, namespace); reason != "" {
+			log.Printf("Policy denied %s in namespace '%s': %s", tool.Name, namespace, reason)
+			message := fmt.Sprintf("%s is not allowed in namespace '%s' by the server policy: %s", tool.Name, namespace, reason)
+			if namespace == "" {
+				message = fmt.Sprintf("%s is not allowed cluster-wide by the server policy: %s", tool.Name, reason)
+			}
+			return toolRefused(tool.Name, namespace, "PolicyDenied", message), nil
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// namespaceCheck returns the policy check of a tool for every namespace it operates on,
+// nil when no policy is loaded
+func namespaceCheck(toolName string) kube.NamespaceCheck {
+	if policy == nil {
+		return nil
+	}
+	return func(namespace string) string {
+		reason := policy.check(toolName, strings.ToLower(namespace))
+		if reason != "" {
+			log.Printf("Policy denied %s in namespace '%s': %s", toolName, namespace, reason)
+		}
+		return reason
+	}
+}
+
+// check returns why a call is denied, or "" when it is allowed. An empty namespace
+// means all namespaces.
+func (p *Policy) check(toolName, namespace string) string {
+	if namespace == "" && matchesAny(p.ClusterScopedTools, toolName) {
+		return ""
+	}
+
+	for _, rule := range p.Rules {
+		if !rule.appliesTo(toolName) {
+			continue
+		}
+
+		for _, pattern := range rule.DenyNamespaces {
+			if namespace == "" {
+				return fmt.Sprintf("cluster-wide calls are denied because namespaces matching '%s' are denied", pattern)
+			}
+			if matches(pattern, namespace) {
+				return fmt.Sprintf("namespace matches denied pattern '%s'", pattern)
+			}
+		}
+
+		if len(rule.AllowNamespaces) == 0 {
+			continue
+		}
+		allowed := false
+		for _, pattern := range rule.AllowNamespaces {
+			if pattern == "*" || (namespace != "" && matches(pattern, namespace)) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed && namespace == "" {
+			return fmt.Sprintf("cluster-wide calls are denied because only the namespaces %v are allowed", rule.AllowNamespaces)
+		}
+		if !allowed {
+			return fmt.Sprintf("namespace is not in the allowed namespaces %v", rule.AllowNamespaces)
+		}
+	}
+	return ""
+}
+
+func (r *PolicyRule) appliesTo(toolName string) bool {
+	if len(r.Tools) > 0 && !matchesAny(r.Tools, toolName) {
+		return false
+	}
+	if len(r.Verbs) == 0 {
+		return true
+	}
+	for _, p := range toolPermissions[toolName] {
+		if matchesAny(r.Verbs, p.Verb) {
+			return true
+		}
+	}
+	for _, verb := range objectVerbs[toolName] {
+		if matchesAny(r.Verbs, verb) {
+			return true
+		}
+	}
+	return false
+}
+
+// usesCluster reports whether a tool calls the Kubernetes API, tools like the Calculator
+// need no permissions and are outside of the policy
+func usesCluster(toolName string) bool {
+	return len(toolPermissions[toolName]) > 0 || len(objectVerbs[toolName]) > 0
+}
+
+func matchesAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if matches(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func matches(pattern, value string) bool {
+	ok, _ := path.Match(pattern, value)
+	return ok
+}
+
+func toolRefused(toolName, namespace, code, message string) *protocol.CallToolResult {
+	doc, _ := json.Marshal(PolicyError{
+		Error:     message,
+		Code:      code,
+		Tool:      toolName,
+		Namespace: namespace,
+	})
+
+	return &protocol.CallToolResult{
+		Content: []protocol.Content{
+			&protocol.TextContent{
+				Type: "text",
+				Text: string(doc),
+			},
+		},
+		IsError: true,
+	}
+}
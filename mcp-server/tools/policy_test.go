@@ -0,0 +1,179 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+)
+
+func TestPolicyCheck(t *testing.T) {
+	p := &Policy{Rules: []PolicyRule{
+		{Verbs: []string{"patch", "create", "delete"}, DenyNamespaces: []string{"kube-*"}},
+		{Tools: []string{"ServiceRestarter"}, AllowNamespaces: []string{"shop", "dev-*"}},
+		{Tools: []string{"Secret*"}, DenyNamespaces: []string{"vault"}},
+	}}
+
+	tests := []struct {
+		tool      string
+		namespace string
+		denied    string
+	}{
+		// read-only tools are not matched by the verb rule
+		{"PodFinder", "kube-system", ""},
+		{"PodFinder", "", ""},
+		{"ServiceRestarter", "kube-system", "denied pattern 'kube-*'"},
+		{"ServiceRestarter", "shop", ""},
+		{"ServiceRestarter", "dev-eu", ""},
+		{"ServiceRestarter", "prod", "not in the allowed namespaces"},
+		{"ServiceRestarter", "", "cluster-wide calls are denied"},
+		{"CronJobTrigger", "batch", ""},
+		{"CronJobTrigger", "kube-public", "denied pattern 'kube-*'"},
+		// matched through the verbs it uses on the objects of the manifest
+		{"ManifestApplier", "kube-system", "denied pattern 'kube-*'"},
+		{"ManifestApplier", "shop", ""},
+		{"NodeDrain", "kube-system", "denied pattern 'kube-*'"},
+		// cluster-scoped tools are checked without a namespace
+		{"NodeCordon", "", "cluster-wide calls are denied"},
+		{"SecretViewer", "vault", "denied pattern 'vault'"},
+		{"SecretViewer", "shop", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tool+"/"+tt.namespace, func(t *testing.T) {
+			reason := p.check(tt.tool, tt.namespace)
+			if tt.denied == "" && reason != "" {
+				t.Errorf("check(%s, %q) denied: %s", tt.tool, tt.namespace, reason)
+			}
+			if tt.denied != "" && !strings.Contains(reason, tt.denied) {
+				t.Errorf("check(%s, %q) = %q, want a denial containing %q", tt.tool, tt.namespace, reason, tt.denied)
+			}
+		})
+	}
+}
+
+func TestNamespaceCheck(t *testing.T) {
+	defer func(p *Policy) { policy = p }(policy)
+
+	policy = nil
+	if check := namespaceCheck("ManifestApplier"); check != nil {
+		t.Fatalf("namespaceCheck() without a policy = %v, want nil", check)
+	}
+
+	policy = &Policy{Rules: []PolicyRule{{DenyNamespaces: []string{"kube-*"}}}}
+	check := namespaceCheck("ManifestApplier")
+	if reason := check("Kube-System"); reason == "" {
+		t.Errorf("check(Kube-System) allowed, namespaces are compared in lower case")
+	}
+	if reason := check("shop"); reason != "" {
+		t.Errorf("check(shop) denied: %s", reason)
+	}
+	// cluster scoped objects are denied as soon as a namespace is
+	if reason := check(""); reason == "" {
+		t.Errorf("check(\"\") allowed a cluster scoped object")
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	SetReadOnly(true)
+	defer SetReadOnly(false)
+
+	called := false
+	handler := func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+		called = true
+		return textResult("{}", false), nil
+	}
+
+	tests := []struct {
+		tool    string
+		handler server.ToolHandlerFunc
+		args    string
+		refused bool
+		called  bool
+	}{
+		{"PodFinder", handler, `{"namespace":"shop"}`, false, true},
+		{"ServiceRestarter", handler, `{"service":"cart","namespace":"shop"}`, true, false},
+		// tools with a dry run are refused by their handler when asked to change the cluster
+		{"ManifestApplier", handler, `{"manifest":"kind: ConfigMap"}`, false, true},
+		{"ManifestApplier", handleManifestApplier, `{"manifest":"kind: ConfigMap","apply":true,"fieldManager":"ops"}`, true, false},
+		{"NodeDrain", handler, `{"node":"worker-1","dryRun":true}`, false, true},
+		{"NodeDrain", handleNodeDrain, `{"node":"worker-1"}`, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tool+tt.args, func(t *testing.T) {
+			called = false
+			req := &protocol.CallToolRequest{Name: tt.tool, RawArguments: json.RawMessage(tt.args)}
+			result, err := WithPolicy(&protocol.Tool{Name: tt.tool}, tt.handler)(context.Background(), req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var doc PolicyError
+			json.Unmarshal([]byte(result.Content[0].(*protocol.TextContent).Text), &doc)
+			if refused := result.IsError && doc.Code == "ReadOnly"; refused != tt.refused {
+				t.Errorf("refused = %v, want %v: %+v", refused, tt.refused, doc)
+			}
+			if called != tt.called {
+				t.Errorf("handler called = %v, want %v", called, tt.called)
+			}
+		})
+	}
+}
+
+func TestClusterScopedCalls(t *testing.T) {
+	defer func(p *Policy) { policy = p }(policy)
+	policy = &Policy{
+		Rules:              []PolicyRule{{AllowNamespaces: []string{"shop"}}},
+		ClusterScopedTools: []string{"NodeFinder", "Node*Viewer"},
+	}
+
+	tests := []struct {
+		get    func() (*protocol.Tool, server.ToolHandlerFunc)
+		args   string
+		denied bool
+	}{
+		{GetPodFinderTool, `{"namespace":"shop"}`, false},
+		{GetPodFinderTool, `{"namespace":"prod"}`, true},
+		// all namespaces
+		{GetPodFinderTool, `{}`, true},
+		// cluster-scoped tools are denied unless listed
+		{GetNodeCordonTool, `{"node":"worker-1","cordon":true}`, true},
+		{GetNodeDrainTool, `{"node":"worker-1","dryRun":true}`, true},
+		{GetPermissionsReportTool, `{}`, true},
+		{GetNodeFinderTool, `{}`, false},
+		{GetNodeMetricsViewerTool, `{}`, false},
+		// not a Kubernetes tool
+		{GetCalculatorTool, `{"operation":"add","a":1,"b":2}`, false},
+	}
+
+	for _, tt := range tests {
+		tool, _ := tt.get()
+		t.Run(tool.Name+tt.args, func(t *testing.T) {
+			called := false
+			handler := func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+				called = true
+				return textResult("{}", false), nil
+			}
+
+			req := &protocol.CallToolRequest{Name: tool.Name, RawArguments: json.RawMessage(tt.args)}
+			result, err := WithPolicy(tool, handler)(context.Background(), req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if denied := result.IsError && !called; denied != tt.denied {
+				t.Errorf("denied = %v, want %v: %s", denied, tt.denied, result.Content[0].(*protocol.TextContent).Text)
+			}
+		})
+	}
+}
+
+func textResult(text string, isError bool) *protocol.CallToolResult {
+	return &protocol.CallToolResult{
+		Content: []protocol.Content{&protocol.TextContent{Type: "text", Text: text}},
+		IsError: isError,
+	}
+}
@@ -0,0 +1,65 @@
+package tools
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"uf/mcp/pkg/kube"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+)
+
+// ProgressNotifier delivers a notifications/progress message to the MCP client
+type ProgressNotifier func(ctx context.Context, notify *protocol.ProgressNotification) error
+
+var (
+	progressNotifier ProgressNotifier
+)
+
+// SetProgressNotifier registers the function used by long running tools to report progress
+func SetProgressNotifier(notifier ProgressNotifier) {
+	progressNotifier = notifier
+}
+
+// progressReporter returns a kube.ProgressFunc that logs progress and, when the client
+// asked for it with a progress token, forwards it as MCP progress notifications
+func progressReporter(ctx context.Context, req *protocol.CallToolRequest) kube.ProgressFunc {
+	var token protocol.ProgressToken
+	if req.Meta != nil {
+		token = req.Meta.ProgressToken
+	}
+
+	// concurrent steps may report out of order, the progress sent to the client must not
+	// go backwards
+	var mu sync.Mutex
+	last := -1.0
+
+	return func(progress, total float64, message string) {
+		log.Printf("%s progress %.0f/%.0f: %s", req.Name, progress, total, message)
+
+		if progressNotifier == nil || token == nil {
+			return
+		}
+
+		mu.Lock()
+		stale := progress < last
+		if !stale {
+			last = progress
+		}
+		mu.Unlock()
+		if stale {
+			return
+		}
+
+		notify := &protocol.ProgressNotification{
+			ProgressToken: token,
+			Progress:      progress,
+			Total:         total,
+			Message:       message,
+		}
+		if err := progressNotifier(ctx, notify); err != nil {
+			log.Printf("Failed to send progress notification: %v", err)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"uf/mcp/pkg/kube"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+)
+
+type CertExpiryScanner struct {
+	Namespace string `json:"namespace" description:"Optional Namespace to scan. All namespaces are scanned when omitted"`
+	Days      int    `json:"days" description:"Report certificates expiring within this number of days. Defaults to 30"`
+}
+
+// Name of the tool
+func (c *CertExpiryScanner) Name() string {
+	return "CertExpiryScanner"
+}
+
+// Description of the tool
+func (c *CertExpiryScanner) Description() string {
+	desc := []string{
+		"Tool to find TLS certificates that are about to expire.",
+		"Parses the kubernetes.io/tls secrets referenced by Ingresses, cluster-wide or in a given Namespace.",
+		"Returns the certificates expiring within N days with their subject, DNS names, expiry date and the Ingresses using them.",
+	}
+	return strings.Join(desc, "\n")
+}
+
+func GetCertExpiryScannerTool() (*protocol.Tool, server.ToolHandlerFunc) {
+	log.Print("Initializing CertExpiryScanner tool")
+
+	toolStruct := CertExpiryScanner{}
+
+	tool, err := protocol.NewTool(
+		toolStruct.Name(),
+		toolStruct.Description(),
+		toolStruct,
+	)
+	if err != nil {
+		log.Fatalf("Failed to create tool: %v", err)
+	}
+
+	return tool, handleCertExpiryScanner
+}
+
+// Tool execution logic
+func handleCertExpiryScanner(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+	var request CertExpiryScanner
+
+	if err := protocol.VerifyAndUnmarshal(req.RawArguments, &request); err != nil {
+		return nil, err
+	}
+
+	report, err := kube.ScanIngressCertificates(request.Namespace, request.Days)
+	if err != nil {
+		return &protocol.CallToolResult{
+			Content: []protocol.Content{
+				&protocol.TextContent{
+					Type: "text",
+					Text: fmt.Sprintf(`{"Error":"%v"}`, err),
+				},
+			},
+			IsError: true,
+		}, err
+	}
+
+	jsonDoc, err := json.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal output: %v", err)
+	}
+
+	return &protocol.CallToolResult{
+		Content: []protocol.Content{
+			&protocol.TextContent{
+				Type: "text",
+				Text: string(jsonDoc),
+			},
+		},
+		IsError: false,
+	}, nil
+}
@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"uf/mcp/pkg/kube"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+)
+
+type ConfigMapViewer struct {
+	Namespace string `json:"namespace" description:"Name of the Namespace where the ConfigMap is defined" required:"true"`
+	ConfigMap string `json:"configmap" description:"Optional name of the ConfigMap. All ConfigMaps of the namespace are returned when omitted"`
+}
+
+// Name of the tool
+func (c *ConfigMapViewer) Name() string {
+	return "ConfigMapViewer"
+}
+
+// Description of the tool
+func (c *ConfigMapViewer) Description() string {
+	desc := []string{
+		"Tool to view the keys and values of ConfigMaps in a given Kubernetes Namespace.",
+		"Long values are truncated and binary values are reported by size only.",
+	}
+	return strings.Join(desc, "\n")
+}
+
+func GetConfigMapViewerTool() (*protocol.Tool, server.ToolHandlerFunc) {
+	log.Print("Initializing ConfigMapViewer tool")
+
+	toolStruct := ConfigMapViewer{}
+
+	tool, err := protocol.NewTool(
+		toolStruct.Name(),
+		toolStruct.Description(),
+		toolStruct,
+	)
+	if err != nil {
+		log.Fatalf("Failed to create tool: %v", err)
+	}
+
+	return tool, handleConfigMapViewer
+}
+
+// Tool execution logic
+func handleConfigMapViewer(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+	var request ConfigMapViewer
+
+	if err := protocol.VerifyAndUnmarshal(req.RawArguments, &request); err != nil {
+		return nil, err
+	}
+
+	configMaps, err := kube.GetConfigMaps(request.Namespace, request.ConfigMap)
+	if err != nil {
+		return &protocol.CallToolResult{
+			Content: []protocol.Content{
+				&protocol.TextContent{
+					Type: "text",
+					Text: fmt.Sprintf(`{"Error":"%v"}`, err),
+				},
+			},
+			IsError: true,
+		}, err
+	}
+
+	jsonDoc, err := json.Marshal(configMaps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal output: %v", err)
+	}
+
+	return &protocol.CallToolResult{
+		Content: []protocol.Content{
+			&protocol.TextContent{
+				Type: "text",
+				Text: string(jsonDoc),
+			},
+		},
+		IsError: false,
+	}, nil
+}
@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"uf/mcp/pkg/kube"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+)
+
+type HPAViewer struct {
+	Namespace string `json:"namespace" description:"Name of the Namespace for which the HorizontalPodAutoscalers are requested" required:"true"`
+}
+
+// Name of the tool
+func (h *HPAViewer) Name() string {
+	return "HPAViewer"
+}
+
+// Description of the tool
+func (h *HPAViewer) Description() string {
+	desc := []string{
+		"Tool to view the HorizontalPodAutoscalers (HPA) of a given Kubernetes Namespace.",
+		"Returns the target workload, min/max/current/desired replicas, current vs. target metric values and the AbleToScale, ScalingActive and ScalingLimited conditions.",
+		"Useful to explain why an application is not scaling.",
+	}
+	return strings.Join(desc, "\n")
+}
+
+func GetHPAViewerTool() (*protocol.Tool, server.ToolHandlerFunc) {
+	log.Print("Initializing HPAViewer tool")
+
+	toolStruct := HPAViewer{}
+
+	tool, err := protocol.NewTool(
+		toolStruct.Name(),
+		toolStruct.Description(),
+		toolStruct,
+	)
+	if err != nil {
+		log.Fatalf("Failed to create tool: %v", err)
+	}
+
+	return tool, handleHPAViewer
+}
+
+// Tool execution logic
+func handleHPAViewer(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+	var request HPAViewer
+
+	if err := protocol.VerifyAndUnmarshal(req.RawArguments, &request); err != nil {
+		return nil, err
+	}
+
+	hpas, err := kube.GetHPAs(request.Namespace)
+	if err != nil {
+		return &protocol.CallToolResult{
+			Content: []protocol.Content{
+				&protocol.TextContent{
+					Type: "text",
+					Text: fmt.Sprintf(`{"Error":"%v"}`, err),
+				},
+			},
+			IsError: true,
+		}, err
+	}
+
+	jsonDoc, err := json.Marshal(hpas)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal output: %v", err)
+	}
+
+	return &protocol.CallToolResult{
+		Content: []protocol.Content{
+			&protocol.TextContent{
+				Type: "text",
+				Text: string(jsonDoc),
+			},
+		},
+		IsError: false,
+	}, nil
+}
@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"uf/mcp/pkg/kube"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+)
+
+type NodeMetricsViewer struct{}
+
+// Name of the tool
+func (n *NodeMetricsViewer) Name() string {
+	return "NodeMetricsViewer"
+}
+
+// Description of the tool
+func (n *NodeMetricsViewer) Description() string {
+	desc := []string{
+		"Displays live CPU and memory usage of every node in the Kubernetes cluster, busiest nodes first.",
+		"Usage is also reported as a percentage of the node's allocatable resources.",
+		"Requires metrics-server to be enabled (e.g., in Minikube).",
+	}
+	return strings.Join(desc, "\n")
+}
+
+func GetNodeMetricsViewerTool() (*protocol.Tool, server.ToolHandlerFunc) {
+	log.Print("Initializing NodeMetricsViewer tool")
+
+	toolStruct := NodeMetricsViewer{}
+
+	tool, err := protocol.NewTool(
+		toolStruct.Name(),
+		toolStruct.Description(),
+		toolStruct,
+	)
+	if err != nil {
+		log.Fatalf("Failed to create tool: %v", err)
+	}
+
+	return tool, handleNodeMetricsViewer
+}
+
+// Tool execution logic
+func handleNodeMetricsViewer(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+	var request NodeMetricsViewer
+
+	if err := protocol.VerifyAndUnmarshal(req.RawArguments, &request); err != nil {
+		return nil, err
+	}
+
+	metrics, err := kube.GetNodeCpuMemory()
+	if err != nil {
+		return &protocol.CallToolResult{
+			Content: []protocol.Content{
+				&protocol.TextContent{
+					Type: "text",
+					Text: fmt.Sprintf(`{"Error":"%v"}`, err),
+				},
+			},
+			IsError: true,
+		}, err
+	}
+
+	jsonDoc, err := json.Marshal(metrics)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal output: %v", err)
+	}
+
+	return &protocol.CallToolResult{
+		Content: []protocol.Content{
+			&protocol.TextContent{
+				Type: "text",
+				Text: string(jsonDoc),
+			},
+		},
+		IsError: false,
+	}, nil
+}
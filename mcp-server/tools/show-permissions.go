@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+)
+
+type PermissionsReport struct {
+	Namespace string `json:"namespace" description:"Optional Namespace to check the permissions in. Cluster-wide permissions are checked when omitted"`
+}
+
+// Name of the tool
+func (p *PermissionsReport) Name() string {
+	return "PermissionsReport"
+}
+
+// Description of the tool
+func (p *PermissionsReport) Description() string {
+	desc := []string{
+		"Tool to report which of the MCP server's tools are permitted by the Kubernetes RBAC rules of its service account.",
+		"Runs a SelfSubjectAccessReview for every verb and resource each tool needs and lists the denied ones.",
+		"Useful to explain Forbidden errors returned by other tools.",
+	}
+	return strings.Join(desc, "\n")
+}
+
+func GetPermissionsReportTool() (*protocol.Tool, server.ToolHandlerFunc) {
+	log.Print("Initializing PermissionsReport tool")
+
+	toolStruct := PermissionsReport{}
+
+	tool, err := protocol.NewTool(
+		toolStruct.Name(),
+		toolStruct.Description(),
+		toolStruct,
+	)
+	if err != nil {
+		log.Fatalf("Failed to create tool: %v", err)
+	}
+
+	return tool, handlePermissionsReport
+}
+
+// Tool execution logic
+func handlePermissionsReport(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+	var request PermissionsReport
+
+	if err := protocol.VerifyAndUnmarshal(req.RawArguments, &request); err != nil {
+		return nil, err
+	}
+
+	report, err := CheckToolPermissions(request.Namespace)
+	if err != nil {
+		return &protocol.CallToolResult{
+			Content: []protocol.Content{
+				&protocol.TextContent{
+					Type: "text",
+					Text: fmt.Sprintf(`{"Error":"%v"}`, err),
+				},
+			},
+			IsError: true,
+		}, err
+	}
+
+	jsonDoc, err := json.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal output: %v", err)
+	}
+
+	return &protocol.CallToolResult{
+		Content: []protocol.Content{
+			&protocol.TextContent{
+				Type: "text",
+				Text: string(jsonDoc),
+			},
+		},
+		IsError: false,
+	}, nil
+}
@@ -2,6 +2,7 @@ package tools
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"strings"
@@ -14,6 +15,8 @@ import (
 
 type PodCpuMemoryViewer struct {
 	Namespace string `json:"namespace" description:"Namespace to inspect pod CPU and memory usage" required:"true"`
+	SortBy    string `json:"sortBy" description:"Optional sort key, either cpu or memory. Pods are sorted by descending usage"`
+	Top       int    `json:"top" description:"Optional number of pods to return after sorting, e.g. 5 for the top 5 pods. Pods are sorted by cpu unless sortBy is set"`
 }
 
 // Name of the tool
@@ -25,6 +28,8 @@ func (p *PodCpuMemoryViewer) Name() string {
 func (p *PodCpuMemoryViewer) Description() string {
 	desc := []string{
 		"Displays CPU and memory usage for all pods in a given Kubernetes namespace.",
+		"Usage is reported per pod and per container, along with requests, limits and the percentage of the limit in use.",
+		"Pods can be sorted by cpu or memory and limited to the top N, e.g. to find the top 5 pods by memory.",
 		"Requires metrics-server to be enabled (e.g., in Minikube).",
 	}
 	return strings.Join(desc, "\n")
@@ -69,16 +74,48 @@ func handlePodCpuMemoryViewer(ctx context.Context, req *protocol.CallToolRequest
 		}, err
 	}
 
-	var lines []string
-	for _, m := range metrics {
-		lines = append(lines, fmt.Sprintf("Pod: %s | CPU: %s | Memory: %s", m.Name, m.CPU, m.Memory))
+	// the top N pods are the heaviest ones, not the first ones listed by the API
+	if request.Top > 0 && request.SortBy == "" {
+		request.SortBy = "cpu"
+	}
+
+	if request.SortBy != "" {
+		if err := kube.SortPodCpuMemory(metrics, request.SortBy); err != nil {
+			return &protocol.CallToolResult{
+				Content: []protocol.Content{
+					&protocol.TextContent{
+						Type: "text",
+						Text: fmt.Sprintf(`{"Error":"%v"}`, err),
+					},
+				},
+				IsError: true,
+			}, err
+		}
+	}
+
+	if request.Top > 0 && request.Top < len(metrics) {
+		metrics = metrics[:request.Top]
+	}
+
+	jsonDoc, err := json.Marshal(metrics)
+	if err != nil {
+		err = fmt.Errorf("failed to marshal output: %v", err)
+		return &protocol.CallToolResult{
+			Content: []protocol.Content{
+				&protocol.TextContent{
+					Type: "text",
+					Text: fmt.Sprintf(`{"Error":"%v"}`, err),
+				},
+			},
+			IsError: true,
+		}, err
 	}
 
 	return &protocol.CallToolResult{
 		Content: []protocol.Content{
 			&protocol.TextContent{
 				Type: "text",
-				Text: strings.Join(lines, "\n"),
+				Text: string(jsonDoc),
 			},
 		},
 		IsError: false,
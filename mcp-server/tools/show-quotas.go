@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"uf/mcp/pkg/kube"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+)
+
+type QuotaViewer struct {
+	Namespace string  `json:"namespace" description:"Name of the Namespace whose quotas are inspected" required:"true"`
+	Threshold float64 `json:"threshold" description:"Optional utilization percentage above which a quota resource is flagged. Defaults to 80"`
+}
+
+// Name of the tool
+func (q *QuotaViewer) Name() string {
+	return "QuotaViewer"
+}
+
+// Description of the tool
+func (q *QuotaViewer) Description() string {
+	desc := []string{
+		"Tool to inspect the ResourceQuotas and LimitRanges of a given Kubernetes Namespace.",
+		"Reports hard vs. used values of each quota resource and the default requests/limits applied by LimitRanges.",
+		"Resources above the utilization threshold are flagged. Useful when deployments fail with 'exceeded quota'.",
+	}
+	return strings.Join(desc, "\n")
+}
+
+func GetQuotaViewerTool() (*protocol.Tool, server.ToolHandlerFunc) {
+	log.Print("Initializing QuotaViewer tool")
+
+	toolStruct := QuotaViewer{}
+
+	tool, err := protocol.NewTool(
+		toolStruct.Name(),
+		toolStruct.Description(),
+		toolStruct,
+	)
+	if err != nil {
+		log.Fatalf("Failed to create tool: %v", err)
+	}
+
+	return tool, handleQuotaViewer
+}
+
+// Tool execution logic
+func handleQuotaViewer(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+	var request QuotaViewer
+
+	if err := protocol.VerifyAndUnmarshal(req.RawArguments, &request); err != nil {
+		return nil, err
+	}
+
+	report, err := kube.GetQuotas(request.Namespace, request.Threshold)
+	if err != nil {
+		return &protocol.CallToolResult{
+			Content: []protocol.Content{
+				&protocol.TextContent{
+					Type: "text",
+					Text: fmt.Sprintf(`{"Error":"%v"}`, err),
+				},
+			},
+			IsError: true,
+		}, err
+	}
+
+	jsonDoc, err := json.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal output: %v", err)
+	}
+
+	return &protocol.CallToolResult{
+		Content: []protocol.Content{
+			&protocol.TextContent{
+				Type: "text",
+				Text: string(jsonDoc),
+			},
+		},
+		IsError: false,
+	}, nil
+}
@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"uf/mcp/pkg/kube"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+)
+
+type SecretViewer struct {
+	Namespace string `json:"namespace" description:"Name of the Namespace where the Secret is defined" required:"true"`
+	Secret    string `json:"secret" description:"Optional name of the Secret. All Secrets of the namespace are returned when omitted"`
+}
+
+// Name of the tool
+func (s *SecretViewer) Name() string {
+	return "SecretViewer"
+}
+
+// Description of the tool
+func (s *SecretViewer) Description() string {
+	desc := []string{
+		"Tool to inspect Secrets in a given Kubernetes Namespace without revealing their values.",
+		"Returns the type of each Secret and the names and sizes of its keys.",
+		"For TLS secrets the certificate subject, issuer, DNS names (SANs) and expiry date are parsed from tls.crt.",
+		"Secret values are never returned.",
+	}
+	return strings.Join(desc, "\n")
+}
+
+func GetSecretViewerTool() (*protocol.Tool, server.ToolHandlerFunc) {
+	log.Print("Initializing SecretViewer tool")
+
+	toolStruct := SecretViewer{}
+
+	tool, err := protocol.NewTool(
+		toolStruct.Name(),
+		toolStruct.Description(),
+		toolStruct,
+	)
+	if err != nil {
+		log.Fatalf("Failed to create tool: %v", err)
+	}
+
+	return tool, handleSecretViewer
+}
+
+// Tool execution logic
+func handleSecretViewer(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+	var request SecretViewer
+
+	if err := protocol.VerifyAndUnmarshal(req.RawArguments, &request); err != nil {
+		return nil, err
+	}
+
+	secrets, err := kube.GetSecrets(request.Namespace, request.Secret)
+	if err != nil {
+		return &protocol.CallToolResult{
+			Content: []protocol.Content{
+				&protocol.TextContent{
+					Type: "text",
+					Text: fmt.Sprintf(`{"Error":"%v"}`, err),
+				},
+			},
+			IsError: true,
+		}, err
+	}
+
+	jsonDoc, err := json.Marshal(secrets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal output: %v", err)
+	}
+
+	return &protocol.CallToolResult{
+		Content: []protocol.Content{
+			&protocol.TextContent{
+				Type: "text",
+				Text: string(jsonDoc),
+			},
+		},
+		IsError: false,
+	}, nil
+}
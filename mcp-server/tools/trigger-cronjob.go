@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"uf/mcp/pkg/kube"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/ThinkInAIXYZ/go-mcp/server"
+)
+
+type CronJobTrigger struct {
+	Namespace string `json:"namespace" description:"Name of the Namespace where the cronjob is defined" required:"true"`
+	CronJob   string `json:"cronjob" description:"Name of the CronJob to trigger" required:"true"`
+}
+
+// Name of the tool
+func (c *CronJobTrigger) Name() string {
+	return "CronJobTrigger"
+}
+
+// Description of the tool
+func (c *CronJobTrigger) Description() string {
+	desc := []string{
+		"Tool to manually trigger a Kubernetes CronJob in a given Namespace.",
+		"This creates a new Job from the CronJob's job template, like 'kubectl create job --from=cronjob/<name>'.",
+		"This tool modifies the cluster.",
+	}
+	return strings.Join(desc, "\n")
+}
+
+func GetCronJobTriggerTool() (*protocol.Tool, server.ToolHandlerFunc) {
+	log.Print("Initializing CronJobTrigger tool")
+
+	toolStruct := CronJobTrigger{}
+
+	tool, err := protocol.NewTool(
+		toolStruct.Name(),
+		toolStruct.Description(),
+		toolStruct,
+	)
+	if err != nil {
+		log.Fatalf("Failed to create tool: %v", err)
+	}
+
+	return tool, handleCronJobTrigger
+}
+
+// Tool execution logic
+func handleCronJobTrigger(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+	var request CronJobTrigger
+
+	if err := protocol.VerifyAndUnmarshal(req.RawArguments, &request); err != nil {
+		return nil, err
+	}
+
+	output, err := kube.TriggerCronJob(request.Namespace, request.CronJob)
+	if err != nil {
+		return &protocol.CallToolResult{
+			Content: []protocol.Content{
+				&protocol.TextContent{
+					Type: "text",
+					Text: fmt.Sprintf(`{"Error":"%v"}`, err),
+				},
+			},
+			IsError: true,
+		}, err
+	}
+
+	jsonDoc, err := json.Marshal(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal output: %v", err)
+	}
+
+	return &protocol.CallToolResult{
+		Content: []protocol.Content{
+			&protocol.TextContent{
+				Type: "text",
+				Text: string(jsonDoc),
+			},
+		},
+		IsError: false,
+	}, nil
+}
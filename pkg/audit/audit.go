@@ -0,0 +1,247 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+	OutcomeDenied  = "denied"
+)
+
+// Record is one tool invocation, written as a single JSON line
+type Record struct {
+	Timestamp   time.Time      `json:"timestamp"`
+	Source      string         `json:"source"`
+	Identity    string         `json:"identity"`
+	ChatMessage string         `json:"chatMessage,omitempty"`
+	Tool        string         `json:"tool"`
+	Arguments   map[string]any `json:"arguments,omitempty"`
+	Outcome     string         `json:"outcome"`
+	Error       string         `json:"error,omitempty"`
+	DurationMs  int64          `json:"durationMs"`
+	// objects named by the arguments of a mutating tool, whatever the outcome
+	Target []string `json:"target,omitempty"`
+	// objects actually changed, set once the call succeeded outside of a dry run
+	Objects []string `json:"objects,omitempty"`
+}
+
+type Filter struct {
+	Identity string
+	Tool     string
+	Outcome  string
+	Since    time.Time
+	Limit    int
+}
+
+// Logger appends records to a JSON-lines file, rotating it to file.1 ... file.N
+// once it grows beyond maxSize bytes
+type Logger struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+func NewLogger(path string, maxSizeMB, maxBackups int) (*Logger, error) {
+	l := &Logger{path: path, maxSize: int64(maxSizeMB) * 1024 * 1024, maxBackups: maxBackups}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *Logger) open() error {
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %v", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat audit log: %v", err)
+	}
+	l.file = file
+	l.size = info.Size()
+	return nil
+}
+
+// Write appends a record, a nil logger discards it
+func (l *Logger) Write(record Record) error {
+	if l == nil {
+		return nil
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %v", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.maxSize > 0 && l.size+int64(len(line)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write audit record: %v", err)
+	}
+	return nil
+}
+
+func (l *Logger) rotate() error {
+	l.file.Close()
+
+	for i := l.maxBackups - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", l.path, i), fmt.Sprintf("%s.%d", l.path, i+1))
+	}
+	if l.maxBackups > 0 {
+		os.Rename(l.path, l.path+".1")
+	} else {
+		os.Remove(l.path)
+	}
+
+	return l.open()
+}
+
+// Query returns the records of the current and rotated files matching the filter, newest first
+func (l *Logger) Query(filter Filter) ([]Record, error) {
+	if l == nil {
+		return nil, fmt.Errorf("audit log is not enabled")
+	}
+
+	// the files are read without the lock, so that a slow query doesn't block the tool
+	// calls writing records. A rotation during the query may skip or repeat some records.
+	l.mu.Lock()
+	files := []string{l.path}
+	for i := 1; i <= l.maxBackups; i++ {
+		files = append(files, fmt.Sprintf("%s.%d", l.path, i))
+	}
+	l.mu.Unlock()
+
+	var records []Record
+	for _, name := range files {
+		matched, err := readRecords(name, filter)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, matched...)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Timestamp.After(records[j].Timestamp) })
+	if filter.Limit > 0 && len(records) > filter.Limit {
+		records = records[:filter.Limit]
+	}
+	return records, nil
+}
+
+func readRecords(name string, filter Filter) ([]Record, error) {
+	file, err := os.Open(name)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %v", err)
+	}
+	defer file.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			continue
+		}
+		if filter.matches(&r) {
+			records = append(records, r)
+		}
+	}
+	return records, scanner.Err()
+}
+
+func (f *Filter) matches(r *Record) bool {
+	if f.Identity != "" && !strings.EqualFold(f.Identity, r.Identity) {
+		return false
+	}
+	if f.Tool != "" && !strings.EqualFold(f.Tool, r.Tool) {
+		return false
+	}
+	if f.Outcome != "" && f.Outcome != r.Outcome {
+		return false
+	}
+	if !f.Since.IsZero() && r.Timestamp.Before(f.Since) {
+		return false
+	}
+	return true
+}
+
+// kind of the object changed by a mutating tool, and the argument naming it. Read-only
+// tools don't affect any object.
+var toolObjects = map[string]struct {
+	Kind string
+	Arg  string
+}{
+	"ServiceRestarter": {"Deployment", "service"},
+	"CronJobTrigger":   {"CronJob", "cronjob"},
+	"NodeCordon":       {"Node", "node"},
+	"NodeDrain":        {"Node", "node"},
+}
+
+// SetObjects records the objects targeted by a mutating tool call, and as changed objects
+// when the call succeeded and was not a dry run. It is called once the outcome is known.
+func (r *Record) SetObjects(args map[string]any) {
+	r.Target = AffectedObjects(r.Tool, args)
+	if dryRun, _ := args["dryRun"].(bool); r.Outcome == OutcomeSuccess && !dryRun {
+		r.Objects = r.Target
+	}
+}
+
+// AffectedObjects names the Kubernetes objects a mutating tool call changes, e.g. Deployment shop/cart
+func AffectedObjects(toolName string, args map[string]any) []string {
+	target, found := toolObjects[toolName]
+	if !found {
+		return nil
+	}
+	name, _ := args[target.Arg].(string)
+	if name == "" {
+		return nil
+	}
+	return []string{objectName(target.Kind, args["namespace"], name)}
+}
+
+// Arguments returns the tool arguments as recorded in the audit log. Manifests may hold
+// Secret data, only their size is kept.
+func Arguments(args map[string]any) map[string]any {
+	recorded := make(map[string]any, len(args))
+	for key, value := range args {
+		if s, ok := value.(string); ok && key == "manifest" {
+			value = fmt.Sprintf("<%d bytes>", len(s))
+		}
+		recorded[key] = value
+	}
+	return recorded
+}
+
+func objectName(kind string, namespace any, name string) string {
+	if ns, _ := namespace.(string); ns != "" {
+		return fmt.Sprintf("%s %s/%s", kind, ns, name)
+	}
+	return fmt.Sprintf("%s %s", kind, name)
+}
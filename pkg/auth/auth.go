@@ -0,0 +1,157 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+type contextKey string
+
+const (
+	identityKey contextKey = "identity"
+
+	// identity of callers when the server runs without authentication
+	Anonymous = "anonymous"
+)
+
+// TokenFile maps bearer tokens to caller identities
+//
+//	tokens:
+//	  - identity: chat-client
+//	    token: <random string>
+type TokenFile struct {
+	Tokens []struct {
+		Identity string `json:"identity"`
+		Token    string `json:"token"`
+	} `json:"tokens"`
+}
+
+type Authenticator struct {
+	// sha256 of the token -> identity, so that lookups don't leak timing of the raw tokens
+	tokens map[[sha256.Size]byte]string
+	mTLS   bool
+}
+
+// NewAuthenticator loads the bearer tokens of tokenFile (optional) and accepts verified
+// client certificates when mTLS is true
+func NewAuthenticator(tokenFile string, mTLS bool) (*Authenticator, error) {
+	a := &Authenticator{tokens: map[[sha256.Size]byte]string{}, mTLS: mTLS}
+	if tokenFile == "" {
+		return a, nil
+	}
+
+	content, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token file: %v", err)
+	}
+
+	tf := TokenFile{}
+	if err := yaml.UnmarshalStrict(content, &tf); err != nil {
+		return nil, fmt.Errorf("invalid token file '%s': %v", tokenFile, err)
+	}
+
+	for i, t := range tf.Tokens {
+		if t.Identity == "" || t.Token == "" {
+			return nil, fmt.Errorf("token %d in '%s' needs an identity and a token", i+1, tokenFile)
+		}
+		a.tokens[sha256.Sum256([]byte(t.Token))] = t.Identity
+	}
+	return a, nil
+}
+
+// Enabled reports whether any authentication method is configured
+func (a *Authenticator) Enabled() bool {
+	return len(a.tokens) > 0 || a.mTLS
+}
+
+// Middleware rejects unauthenticated requests and stores the caller identity in the request context
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.Enabled() {
+			next.ServeHTTP(w, r.WithContext(WithIdentity(r.Context(), Anonymous)))
+			return
+		}
+
+		identity, err := a.authenticate(r)
+		if err != nil {
+			log.Printf("Unauthorized request from %s: %v", r.RemoteAddr, err)
+			w.Header().Set("WWW-Authenticate", `Bearer realm="mcp"`)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{"Error": err.Error()})
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithIdentity(r.Context(), identity)))
+	})
+}
+
+func (a *Authenticator) authenticate(r *http.Request) (string, error) {
+	if header := r.Header.Get("Authorization"); header != "" {
+		token, found := strings.CutPrefix(header, "Bearer ")
+		if !found {
+			return "", fmt.Errorf("unsupported authorization scheme")
+		}
+		sum := sha256.Sum256([]byte(token))
+		for known, identity := range a.tokens {
+			if subtle.ConstantTimeCompare(sum[:], known[:]) == 1 {
+				return identity, nil
+			}
+		}
+		return "", fmt.Errorf("invalid bearer token")
+	}
+
+	// the TLS handshake already verified the chain against the client CA
+	if a.mTLS && r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		cert := r.TLS.VerifiedChains[0][0]
+		return "cert:" + cert.Subject.CommonName, nil
+	}
+
+	return "", fmt.Errorf("missing credentials")
+}
+
+// ServerTLSConfig verifies client certificates against clientCAFile. Certificates are
+// required when requireCert is true, otherwise a bearer token may be used instead.
+func ServerTLSConfig(clientCAFile string, requireCert bool) (*tls.Config, error) {
+	pem, err := os.ReadFile(clientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificate found in client CA '%s'", clientCAFile)
+	}
+
+	clientAuth := tls.VerifyClientCertIfGiven
+	if requireCert {
+		clientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return &tls.Config{
+		ClientCAs:  pool,
+		ClientAuth: clientAuth,
+		MinVersion: tls.VersionTLS12,
+	}, nil
+}
+
+func WithIdentity(ctx context.Context, identity string) context.Context {
+	return context.WithValue(ctx, identityKey, identity)
+}
+
+// IdentityFromContext returns the authenticated caller, or "" when unknown
+func IdentityFromContext(ctx context.Context) string {
+	identity, _ := ctx.Value(identityKey).(string)
+	return identity
+}
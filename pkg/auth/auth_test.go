@@ -0,0 +1,261 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+// identityHandler answers with the identity stored in the request context
+var identityHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, IdentityFromContext(r.Context()))
+})
+
+func TestNewAuthenticator(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		tokens  int
+		wantErr string
+	}{
+		{"tokens", "tokens:\n  - identity: chat-client\n    token: s3cr3t\n  - identity: cli\n    token: other\n", 2, ""},
+		{"json", `{"tokens":[{"identity":"chat-client","token":"s3cr3t"}]}`, 1, ""},
+		{"missing token", "tokens:\n  - identity: chat-client\n", 0, "needs an identity and a token"},
+		{"missing identity", "tokens:\n  - token: s3cr3t\n", 0, "needs an identity and a token"},
+		{"unknown field", "tokens:\n  - identity: chat-client\n    token: s3cr3t\n    role: admin\n", 0, "invalid token file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := NewAuthenticator(writeFile(t, "tokens.yaml", tt.content), false)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("NewAuthenticator() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(a.tokens) != tt.tokens || !a.Enabled() {
+				t.Errorf("got %d tokens, want %d", len(a.tokens), tt.tokens)
+			}
+		})
+	}
+
+	if _, err := NewAuthenticator(filepath.Join(t.TempDir(), "missing.yaml"), false); err == nil {
+		t.Errorf("NewAuthenticator() of a missing file succeeded")
+	}
+	if a, err := NewAuthenticator("", false); err != nil || a.Enabled() {
+		t.Errorf("NewAuthenticator() without tokens or mTLS = %v, %v, want a disabled authenticator", a, err)
+	}
+}
+
+func TestTokensAreHashed(t *testing.T) {
+	a, err := NewAuthenticator(writeFile(t, "tokens.yaml", "tokens:\n  - identity: chat-client\n    token: s3cr3t\n"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if identity := a.tokens[sha256.Sum256([]byte("s3cr3t"))]; identity != "chat-client" {
+		t.Errorf("token is not stored under its sha256, got %q", identity)
+	}
+	for key := range a.tokens {
+		if strings.Contains(string(key[:]), "s3cr3t") {
+			t.Errorf("the raw token is kept in memory")
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	tokenFile := writeFile(t, "tokens.yaml", "tokens:\n  - identity: chat-client\n    token: s3cr3t\n")
+	verified := &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: "ops"}}}}}
+	// a certificate presented by the client but not verified against the client CA
+	unverified := &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "ops"}}}}
+
+	tests := []struct {
+		name     string
+		tokens   string
+		mTLS     bool
+		header   string
+		tls      *tls.ConnectionState
+		status   int
+		identity string
+	}{
+		{name: "authentication disabled", status: http.StatusOK, identity: Anonymous},
+		{name: "valid token", tokens: tokenFile, header: "Bearer s3cr3t", status: http.StatusOK, identity: "chat-client"},
+		{name: "invalid token", tokens: tokenFile, header: "Bearer s3cr3", status: http.StatusUnauthorized},
+		{name: "token with another scheme", tokens: tokenFile, header: "Basic s3cr3t", status: http.StatusUnauthorized},
+		{name: "missing credentials", tokens: tokenFile, status: http.StatusUnauthorized},
+		{name: "verified certificate", mTLS: true, tls: verified, status: http.StatusOK, identity: "cert:ops"},
+		{name: "unverified certificate", mTLS: true, tls: unverified, status: http.StatusUnauthorized},
+		{name: "certificate without mTLS", tokens: tokenFile, tls: verified, status: http.StatusUnauthorized},
+		// an invalid token is rejected, the certificate is not used as a fallback
+		{name: "invalid token with a certificate", mTLS: true, header: "Bearer wrong", tls: verified, status: http.StatusUnauthorized},
+		{name: "token with mTLS", tokens: tokenFile, mTLS: true, header: "Bearer s3cr3t", status: http.StatusOK, identity: "chat-client"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := NewAuthenticator(tt.tokens, tt.mTLS)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			req.TLS = tt.tls
+			rec := httptest.NewRecorder()
+			a.Middleware(identityHandler).ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if tt.status == http.StatusUnauthorized {
+				if rec.Header().Get("WWW-Authenticate") == "" {
+					t.Errorf("401 without a WWW-Authenticate header")
+				}
+				return
+			}
+			if body := rec.Body.String(); body != tt.identity {
+				t.Errorf("identity = %q, want %q", body, tt.identity)
+			}
+		})
+	}
+}
+
+// issue creates an ECDSA certificate signed by parent, self-signed when parent is nil
+func issue(t *testing.T, cn string, isCA bool, parent *tls.Certificate) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+
+	signer, signerKey := template, any(key)
+	if parent != nil {
+		signer, signerKey = parent.Leaf, parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+// TestMutualTLS authenticates clients over a real TLS handshake, so that only
+// certificates verified against the client CA end up in VerifiedChains
+func TestMutualTLS(t *testing.T) {
+	ca := issue(t, "mcp-client-ca", true, nil)
+	caFile := writeFile(t, "ca.crt", string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Certificate[0]})))
+
+	a, err := NewAuthenticator("", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewUnstartedServer(a.Middleware(identityHandler))
+	if server.TLS, err = ServerTLSConfig(caFile, false); err != nil {
+		t.Fatal(err)
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	get := func(certs ...tls.Certificate) (*http.Response, error) {
+		transport := server.Client().Transport.(*http.Transport).Clone()
+		transport.TLSClientConfig.Certificates = certs
+		return (&http.Client{Transport: transport}).Get(server.URL)
+	}
+
+	resp, err := get(issue(t, "ops", false, &ca))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != "cert:ops" {
+		t.Errorf("signed certificate: status %d, identity %q, want cert:ops", resp.StatusCode, body)
+	}
+
+	// the certificate is optional at the TLS level, the middleware rejects the request
+	resp, err = get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("no certificate: status %d, want 401", resp.StatusCode)
+	}
+
+	// a certificate with the same name but another issuer fails the handshake
+	if resp, err := get(issue(t, "ops", false, nil)); err == nil {
+		resp.Body.Close()
+		t.Errorf("self-signed certificate: status %d, want a handshake error", resp.StatusCode)
+	}
+}
+
+func TestServerTLSConfig(t *testing.T) {
+	ca := issue(t, "mcp-client-ca", true, nil)
+	caFile := writeFile(t, "ca.crt", string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Certificate[0]})))
+
+	config, err := ServerTLSConfig(caFile, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.ClientAuth != tls.VerifyClientCertIfGiven || config.MinVersion != tls.VersionTLS12 {
+		t.Errorf("ClientAuth = %v, MinVersion = %x", config.ClientAuth, config.MinVersion)
+	}
+
+	config, err = ServerTLSConfig(caFile, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.ClientAuth != tls.RequireAndVerifyClientCert {
+		t.Errorf("requireCert: ClientAuth = %v, want RequireAndVerifyClientCert", config.ClientAuth)
+	}
+
+	if _, err := ServerTLSConfig(writeFile(t, "empty.crt", "not a certificate"), false); err == nil {
+		t.Errorf("ServerTLSConfig() accepted a CA file without certificates")
+	}
+}
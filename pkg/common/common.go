@@ -4,6 +4,7 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strings"
 	custom "uf/mcp/pkg/transport"
 
 	"github.com/ThinkInAIXYZ/go-mcp/client"
@@ -73,6 +74,12 @@ func GetMcpClients() map[string]*client.Client {
 	mcpClients := make(map[string]*client.Client)
 
 	// define a map of environemnt variables to mcp client names
+	// Each server is configured with environment variables sharing the prefix of its URL variable,
+	// e.g. for OCP_MCP_URL:
+	//   OCP_MCP_TOKEN        bearer token presented to the server
+	//   OCP_MCP_CA_CERT      CA bundle verifying the server certificate
+	//   OCP_MCP_CLIENT_CERT  client certificate for mutual TLS
+	//   OCP_MCP_CLIENT_KEY   private key of the client certificate
 	envUrls := map[string]string{
 		"OCP_MCP_URL": "ocp",
 		//"ARGOCD_MCP_URL": "argocd",
@@ -90,6 +97,19 @@ func GetMcpClients() map[string]*client.Client {
 		ct := custom.NewCustomTransport()
 		//ct.Debug = true
 
+		prefix := strings.TrimSuffix(envName, "_URL")
+		ct.Token = os.Getenv(prefix + "_TOKEN")
+
+		caCert := os.Getenv(prefix + "_CA_CERT")
+		clientCert := os.Getenv(prefix + "_CLIENT_CERT")
+		clientKey := os.Getenv(prefix + "_CLIENT_KEY")
+		if caCert != "" || clientCert != "" || clientKey != "" {
+			if err := ct.ConfigureTLS(caCert, clientCert, clientKey); err != nil {
+				log.Printf("Failed to configure TLS for mcp client %s: %v", clientName, err)
+				continue
+			}
+		}
+
 		httpClient := &http.Client{
 			Transport: ct,
 		}
@@ -0,0 +1,101 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Export is the JSON export of a session, meant to be attached to incident tickets
+type Export struct {
+	User       string    `json:"user"`
+	ExportedAt time.Time `json:"exportedAt"`
+	Transcript
+}
+
+func NewExport(user string, transcript *Transcript) *Export {
+	return &Export{User: user, ExportedAt: time.Now().UTC(), Transcript: *transcript}
+}
+
+// Markdown renders the session for postmortems, tool arguments and raw outputs are
+// included as code blocks
+func (e *Export) Markdown() string {
+	var b strings.Builder
+
+	title := e.Title
+	if title == "" {
+		title = "Untitled conversation"
+	}
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	fmt.Fprintf(&b, "- **User:** %s\n", e.User)
+	fmt.Fprintf(&b, "- **Session:** %s\n", e.ID)
+	fmt.Fprintf(&b, "- **Started:** %s\n", e.Created.Format(time.RFC3339))
+	fmt.Fprintf(&b, "- **Last message:** %s\n", e.Updated.Format(time.RFC3339))
+	fmt.Fprintf(&b, "- **Exported:** %s\n", e.ExportedAt.Format(time.RFC3339))
+
+	for _, msg := range e.Messages {
+		role := "User"
+		if msg.Role == "assistant" {
+			role = "Assistant"
+		}
+		fmt.Fprintf(&b, "\n## %s, %s", role, msg.Timestamp.Format(time.RFC3339))
+		if msg.DurationMs > 0 {
+			fmt.Fprintf(&b, " (%s)", duration(msg.DurationMs))
+		}
+		b.WriteString("\n\n")
+
+		if call := msg.ToolCall; call != nil {
+			fmt.Fprintf(&b, "**Tool:** `%s` (%s)\n\n", call.Name, duration(call.DurationMs))
+			if len(call.Arguments) > 0 {
+				args, _ := json.MarshalIndent(call.Arguments, "", "  ")
+				b.WriteString("Arguments:\n\n")
+				codeBlock(&b, "json", string(args))
+			}
+			if call.Error != "" {
+				b.WriteString("Error:\n\n")
+				codeBlock(&b, "", call.Error)
+			}
+			if call.Output != "" {
+				b.WriteString("Raw output:\n\n")
+				codeBlock(&b, outputLanguage(call.Output), call.Output)
+			}
+		}
+
+		b.WriteString(msg.Content)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// codeBlock writes a fenced block whose fence is longer than any backtick run of the content
+func codeBlock(b *strings.Builder, language, content string) {
+	longest, run := 0, 0
+	for _, r := range content {
+		if r == '`' {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+	fence := "```"
+	if longest >= 3 {
+		fence = strings.Repeat("`", longest+1)
+	}
+
+	fmt.Fprintf(b, "%s%s\n%s\n%s\n\n", fence, language, strings.TrimRight(content, "\n"), fence)
+}
+
+func outputLanguage(output string) string {
+	if json.Valid([]byte(output)) {
+		return "json"
+	}
+	return ""
+}
+
+func duration(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).String()
+}
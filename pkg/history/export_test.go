@@ -0,0 +1,122 @@
+package history
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testExport() *Export {
+	start := time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)
+	transcript := &Transcript{
+		Session: Session{ID: "7", Title: "cart pods", Created: start, Updated: start.Add(time.Minute), MessageCount: 2},
+		Messages: []Message{
+			{Role: "user", Content: "why is cart failing?", Timestamp: start},
+			{
+				Role:       "assistant",
+				Content:    "The cart container keeps crashing.",
+				Timestamp:  start.Add(3 * time.Second),
+				DurationMs: 2500,
+				ToolCall: &ToolCall{
+					Name:      "WorkloadDiagnoser",
+					Arguments: map[string]any{"namespace": "shop", "deployment": "cart", "replicas": 3.0},
+					// logs quoting markdown must not close the code block early
+					Output:     "previous logs:\n```\npanic: missing ````CART_DB````\n```",
+					DurationMs: 1200,
+				},
+			},
+		},
+	}
+	export := NewExport("alice", transcript)
+	export.ExportedAt = start.Add(time.Hour)
+	return export
+}
+
+func TestCodeBlock(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"plain", "NAME READY", "```\nNAME READY\n```\n\n"},
+		{"inline backticks", "use `kubectl`", "```\nuse `kubectl`\n```\n\n"},
+		{"fenced content", "```\nx\n```", "````\n```\nx\n```\n````\n\n"},
+		{"longest run wins", "```` and ```", "`````\n```` and ```\n`````\n\n"},
+		{"trailing newlines", "done\n\n", "```\ndone\n```\n\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			codeBlock(&b, "", tt.content)
+			if got := b.String(); got != tt.want {
+				t.Errorf("codeBlock() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	md := testExport().Markdown()
+
+	for _, want := range []string{
+		"# cart pods\n",
+		"- **User:** alice\n",
+		"## Assistant, 2026-03-02T09:30:03Z (2.5s)\n",
+		"**Tool:** `WorkloadDiagnoser` (1.2s)\n",
+		`"deployment": "cart"`,
+		// the output contains runs of up to 4 backticks
+		"Raw output:\n\n`````\nprevious logs:\n```\npanic: missing ````CART_DB````\n```\n`````\n",
+		"The cart container keeps crashing.\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown() is missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestExportJSON(t *testing.T) {
+	export := testExport()
+
+	data, err := json.Marshal(export)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded Export
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&decoded, export) {
+		t.Errorf("round trip = %+v, want %+v", decoded, *export)
+	}
+
+	// the fields attached to incident tickets, under their documented names
+	var doc struct {
+		User     string `json:"user"`
+		Messages []struct {
+			DurationMs int64 `json:"durationMs"`
+			ToolCall   *struct {
+				Name       string         `json:"name"`
+				Arguments  map[string]any `json:"arguments"`
+				Output     string         `json:"output"`
+				DurationMs int64          `json:"durationMs"`
+			} `json:"toolCall"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.User != "alice" || len(doc.Messages) != 2 || doc.Messages[1].ToolCall == nil {
+		t.Fatalf("unexpected export %s", data)
+	}
+	call := doc.Messages[1].ToolCall
+	if call.Name != "WorkloadDiagnoser" || call.Arguments["deployment"] != "cart" || !strings.Contains(call.Output, "CART_DB") {
+		t.Errorf("tool call = %+v", call)
+	}
+	if call.DurationMs != 1200 || doc.Messages[1].DurationMs != 2500 {
+		t.Errorf("timings = %d and %d, want 1200 and 2500", call.DurationMs, doc.Messages[1].DurationMs)
+	}
+}
@@ -0,0 +1,299 @@
+package history
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	maxTitleLength = 60
+)
+
+var (
+	ErrNotFound = errors.New("session not found")
+
+	// users/<user>/<session id>/{meta, messages/<seq>}
+	usersBucket    = []byte("users")
+	metaKey        = []byte("meta")
+	messagesBucket = []byte("messages")
+)
+
+type Session struct {
+	ID           string    `json:"id"`
+	Title        string    `json:"title"`
+	Created      time.Time `json:"created"`
+	Updated      time.Time `json:"updated"`
+	MessageCount int       `json:"messageCount"`
+}
+
+type Message struct {
+	Role      string    `json:"role"`
+	Content   string    `json:"content"`
+	Timestamp time.Time `json:"timestamp"`
+	// time taken to answer, assistant messages only
+	DurationMs int64     `json:"durationMs,omitempty"`
+	ToolCall   *ToolCall `json:"toolCall,omitempty"`
+}
+
+// ToolCall is the MCP tool invoked to answer a message
+type ToolCall struct {
+	Name       string         `json:"name"`
+	Arguments  map[string]any `json:"arguments,omitempty"`
+	Output     string         `json:"output,omitempty"`
+	Error      string         `json:"error,omitempty"`
+	DurationMs int64          `json:"durationMs"`
+}
+
+type Transcript struct {
+	Session
+	Messages []Message `json:"messages"`
+}
+
+// Store keeps the chat sessions of every user in an embedded bbolt database
+type Store struct {
+	db *bolt.DB
+}
+
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history database: %v", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(usersBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize history database: %v", err)
+	}
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// CreateSession starts an empty session, the title is taken from the first user message when empty
+func (s *Store) CreateSession(user, title string) (*Session, error) {
+	now := time.Now().UTC()
+	session := &Session{
+		Title:   title,
+		Created: now,
+		Updated: now,
+	}
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		userBucket, err := tx.Bucket(usersBucket).CreateBucketIfNotExists([]byte(user))
+		if err != nil {
+			return err
+		}
+		// unique per user, unlike timestamps of sessions created at the same time
+		seq, err := userBucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		session.ID = fmt.Sprintf("%d", seq)
+
+		sessionBucket, err := userBucket.CreateBucket([]byte(session.ID))
+		if err != nil {
+			return err
+		}
+		if _, err := sessionBucket.CreateBucket(messagesBucket); err != nil {
+			return err
+		}
+		return putMeta(sessionBucket, session)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %v", err)
+	}
+	return session, nil
+}
+
+// AppendMessage adds a message to the transcript of a session
+func (s *Store) AppendMessage(user, id string, msg Message) error {
+	if msg.Timestamp.IsZero() {
+		msg.Timestamp = time.Now().UTC()
+	}
+
+	return s.update(user, id, func(b *bolt.Bucket, session *Session) error {
+		messages := b.Bucket(messagesBucket)
+		seq, err := messages.NextSequence()
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		if err := messages.Put(sequenceKey(seq), data); err != nil {
+			return err
+		}
+
+		session.MessageCount++
+		session.Updated = msg.Timestamp
+		if session.Title == "" && msg.Role == "user" {
+			session.Title = titleOf(msg.Content)
+		}
+		return nil
+	})
+}
+
+// ListSessions returns the sessions of a user, most recently updated first
+func (s *Store) ListSessions(user string) ([]Session, error) {
+	sessions := []Session{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		userBucket := tx.Bucket(usersBucket).Bucket([]byte(user))
+		if userBucket == nil {
+			return nil
+		}
+		return userBucket.ForEach(func(id, value []byte) error {
+			// sessions are nested buckets, their value is nil
+			if value != nil {
+				return nil
+			}
+			session, err := getMeta(userBucket.Bucket(id))
+			if err != nil {
+				return err
+			}
+			sessions = append(sessions, *session)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %v", err)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].Updated.After(sessions[j].Updated) })
+	return sessions, nil
+}
+
+// GetTranscript returns a session with all its messages
+func (s *Store) GetTranscript(user, id string) (*Transcript, error) {
+	transcript := &Transcript{Messages: []Message{}}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b, err := sessionBucket(tx, user, id)
+		if err != nil {
+			return err
+		}
+		session, err := getMeta(b)
+		if err != nil {
+			return err
+		}
+		transcript.Session = *session
+
+		return b.Bucket(messagesBucket).ForEach(func(_, data []byte) error {
+			var msg Message
+			if err := json.Unmarshal(data, &msg); err != nil {
+				return err
+			}
+			transcript.Messages = append(transcript.Messages, msg)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transcript, nil
+}
+
+func (s *Store) RenameSession(user, id, title string) (*Session, error) {
+	if title == "" {
+		return nil, fmt.Errorf("title must not be empty")
+	}
+
+	var renamed *Session
+	err := s.update(user, id, func(_ *bolt.Bucket, session *Session) error {
+		session.Title = title
+		renamed = session
+		return nil
+	})
+	return renamed, err
+}
+
+func (s *Store) DeleteSession(user, id string) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		if _, err := sessionBucket(tx, user, id); err != nil {
+			return err
+		}
+		return tx.Bucket(usersBucket).Bucket([]byte(user)).DeleteBucket([]byte(id))
+	})
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("failed to delete session: %v", err)
+	}
+	return err
+}
+
+// update runs fn on the bucket and metadata of a session and saves the metadata afterwards
+func (s *Store) update(user, id string, fn func(*bolt.Bucket, *Session) error) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b, err := sessionBucket(tx, user, id)
+		if err != nil {
+			return err
+		}
+		session, err := getMeta(b)
+		if err != nil {
+			return err
+		}
+		if err := fn(b, session); err != nil {
+			return err
+		}
+		return putMeta(b, session)
+	})
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("failed to update session: %v", err)
+	}
+	return err
+}
+
+func sessionBucket(tx *bolt.Tx, user, id string) (*bolt.Bucket, error) {
+	userBucket := tx.Bucket(usersBucket).Bucket([]byte(user))
+	if userBucket == nil {
+		return nil, ErrNotFound
+	}
+	b := userBucket.Bucket([]byte(id))
+	if b == nil {
+		return nil, ErrNotFound
+	}
+	return b, nil
+}
+
+func getMeta(b *bolt.Bucket) (*Session, error) {
+	session := &Session{}
+	if err := json.Unmarshal(b.Get(metaKey), session); err != nil {
+		return nil, fmt.Errorf("invalid session metadata: %v", err)
+	}
+	return session, nil
+}
+
+func putMeta(b *bolt.Bucket, session *Session) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	return b.Put(metaKey, data)
+}
+
+// big endian keys keep the messages in insertion order
+func sequenceKey(seq uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, seq)
+	return key
+}
+
+func titleOf(content string) string {
+	title := strings.Join(strings.Fields(content), " ")
+	runes := []rune(title)
+	if len(runes) > maxTitleLength {
+		return string(runes[:maxTitleLength]) + "..."
+	}
+	return title
+}
@@ -0,0 +1,52 @@
+package history
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestSessionsPerUser(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	// sessions created in the same instant must not collide
+	ids := map[string]bool{}
+	for i := 0; i < 10; i++ {
+		session, err := store.CreateSession("htpasswd:alice", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ids[session.ID] {
+			t.Fatalf("session id %s was returned twice", session.ID)
+		}
+		ids[session.ID] = true
+	}
+
+	other, err := store.CreateSession("oidc:0f3c9a", "alice of the identity provider")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AppendMessage("oidc:0f3c9a", other.ID, Message{Role: "user", Content: "list pods"}); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions, err := store.ListSessions("htpasswd:alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 10 {
+		t.Errorf("htpasswd:alice has %d sessions, want 10", len(sessions))
+	}
+
+	// ids are per user, the session of another user with the same name keeps its messages
+	if transcript, err := store.GetTranscript("htpasswd:alice", other.ID); err != nil || transcript.MessageCount != 0 {
+		t.Errorf("htpasswd:alice sees the messages of oidc:0f3c9a: %+v %v", transcript, err)
+	}
+	if err := store.DeleteSession("oidc:0f3c9a", "999"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("DeleteSession() of an unknown session = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,73 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Permission is a verb on a resource the MCP server's service account may need
+type Permission struct {
+	Verb        string
+	Group       string
+	Resource    string
+	Subresource string
+}
+
+func (p Permission) String() string {
+	resource := p.Resource
+	if p.Subresource != "" {
+		resource += "/" + p.Subresource
+	}
+	if p.Group != "" {
+		resource = p.Group + "/" + resource
+	}
+	return p.Verb + " " + resource
+}
+
+// NamespaceCheck returns why an operation is not allowed in a namespace, or "" when it is.
+// Cluster scoped objects are checked with an empty namespace.
+type NamespaceCheck func(namespace string) string
+
+// DeniedError is returned when a NamespaceCheck refuses an object
+type DeniedError struct {
+	Namespace string
+	Reason    string
+}
+
+func (e *DeniedError) Error() string {
+	if e.Namespace == "" {
+		return fmt.Sprintf("cluster scoped objects are denied: %s", e.Reason)
+	}
+	return fmt.Sprintf("namespace '%s' is denied: %s", e.Namespace, e.Reason)
+}
+
+// CheckPermissions runs a SelfSubjectAccessReview for each permission, in a namespace
+// or cluster-wide when namespace is empty, and returns the ones that are denied
+func CheckPermissions(namespace string, permissions []Permission) ([]Permission, error) {
+	var denied []Permission
+	for _, p := range permissions {
+		review := &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace:   namespace,
+					Verb:        p.Verb,
+					Group:       p.Group,
+					Resource:    p.Resource,
+					Subresource: p.Subresource,
+				},
+			},
+		}
+
+		result, err := clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(context.TODO(), review, metav1.CreateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to review access to '%s': %v", p, err)
+		}
+		if !result.Status.Allowed {
+			denied = append(denied, p)
+		}
+	}
+	return denied, nil
+}
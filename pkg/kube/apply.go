@@ -0,0 +1,293 @@
+package kube
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	DefaultFieldManager = "mcp-server"
+)
+
+type ApplyResult struct {
+	Kind      string        `json:"kind"`
+	Name      string        `json:"name"`
+	Namespace string        `json:"namespace,omitempty"`
+	Action    string        `json:"action"`
+	DryRun    bool          `json:"dryRun"`
+	Changes   []FieldChange `json:"changes,omitempty"`
+}
+
+type FieldChange struct {
+	Path string `json:"path"`
+	Old  any    `json:"old,omitempty"`
+	New  any    `json:"new,omitempty"`
+}
+
+// ApplyManifest server-side applies every object of a YAML (or JSON) manifest and
+// returns a field-level diff against the live objects. With dryRun the API server
+// validates and computes the result (dryRun=All) without persisting anything.
+// When an object fails, the results of the objects applied before it are returned
+// along with the error. The namespace of every object is checked before anything is
+// applied, a *DeniedError is returned when check refuses one of them.
+func ApplyManifest(manifest, namespace, fieldManager string, dryRun bool, check NamespaceCheck) ([]ApplyResult, error) {
+	if fieldManager == "" {
+		fieldManager = DefaultFieldManager
+	}
+
+	objects, err := decodeManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	// kinds defined by a CRD of the manifest itself can only be mapped, and checked,
+	// once the CRD is applied. The other objects are checked before anything is applied.
+	resources := make([]dynamic.ResourceInterface, len(objects))
+	for i, obj := range objects {
+		resource, err := prepareObject(obj, namespace, check)
+		var denied *DeniedError
+		if errors.As(err, &denied) {
+			return nil, fmt.Errorf("%s '%s': %w", obj.GetKind(), obj.GetName(), err)
+		}
+		resources[i] = resource
+	}
+
+	var results []ApplyResult
+	for i, obj := range objects {
+		resource := resources[i]
+		if resource == nil {
+			if resource, err = prepareObject(obj, namespace, check); err != nil {
+				return results, fmt.Errorf("%s '%s': %w", obj.GetKind(), obj.GetName(), err)
+			}
+		}
+
+		result, err := applyObject(obj, resource, fieldManager, dryRun)
+		if err != nil {
+			return results, fmt.Errorf("%s '%s': %v", obj.GetKind(), obj.GetName(), err)
+		}
+		results = append(results, *result)
+	}
+	return results, nil
+}
+
+// prepareObject resolves the resource of an object and checks its namespace
+func prepareObject(obj *unstructured.Unstructured, namespace string, check NamespaceCheck) (dynamic.ResourceInterface, error) {
+	resource, err := resourceFor(obj, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if check != nil {
+		if reason := check(obj.GetNamespace()); reason != "" {
+			return nil, &DeniedError{Namespace: obj.GetNamespace(), Reason: reason}
+		}
+	}
+	return resource, nil
+}
+
+func decodeManifest(manifest string) ([]*unstructured.Unstructured, error) {
+	decoder := yaml.NewYAMLOrJSONDecoder(strings.NewReader(manifest), 4096)
+
+	var objects []*unstructured.Unstructured
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("invalid manifest: %v", err)
+		}
+		// skip empty documents, e.g. a leading '---'
+		if len(obj.Object) == 0 {
+			continue
+		}
+		if obj.GetKind() == "" || obj.GetName() == "" {
+			return nil, fmt.Errorf("invalid manifest: every object needs a kind and a metadata.name")
+		}
+		objects = append(objects, obj)
+	}
+
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("manifest contains no objects")
+	}
+	return objects, nil
+}
+
+// resourceFor maps an object to its resource and sets its namespace: the one of the
+// manifest, else the default namespace, none for cluster scoped objects
+func resourceFor(obj *unstructured.Unstructured, namespace string) (dynamic.ResourceInterface, error) {
+	gvk := obj.GroupVersionKind()
+	mapping, err := restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		// the cached discovery may be stale, e.g. the manifest installs a CRD before its objects
+		restMapper.Reset()
+		if mapping, err = restMapper.RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+			return nil, fmt.Errorf("failed to map kind: %v", err)
+		}
+	}
+
+	var resource dynamic.ResourceInterface
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		if obj.GetNamespace() == "" {
+			obj.SetNamespace(namespace)
+		}
+		if obj.GetNamespace() == "" {
+			return nil, fmt.Errorf("namespace is required")
+		}
+		resource = dynamicClient.Resource(mapping.Resource).Namespace(obj.GetNamespace())
+	} else {
+		obj.SetNamespace("")
+		resource = dynamicClient.Resource(mapping.Resource)
+	}
+	return resource, nil
+}
+
+func applyObject(obj *unstructured.Unstructured, resource dynamic.ResourceInterface, fieldManager string, dryRun bool) (*ApplyResult, error) {
+	live, err := resource.Get(context.TODO(), obj.GetName(), metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get live object: %v", err)
+	}
+	if apierrors.IsNotFound(err) {
+		live = nil
+	}
+
+	data, err := json.Marshal(obj.Object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal object: %v", err)
+	}
+
+	options := metav1.PatchOptions{FieldManager: fieldManager}
+	if dryRun {
+		options.DryRun = []string{metav1.DryRunAll}
+	}
+
+	applied, err := resource.Patch(context.TODO(), obj.GetName(), types.ApplyPatchType, data, options)
+	if err != nil {
+		return nil, fmt.Errorf("server-side apply failed: %v", err)
+	}
+
+	result := &ApplyResult{
+		Kind:      applied.GetKind(),
+		Name:      applied.GetName(),
+		Namespace: applied.GetNamespace(),
+		DryRun:    dryRun,
+	}
+
+	var before map[string]any
+	if live != nil {
+		before = live.Object
+	}
+	result.Changes = diffObjects(before, applied.Object, applied.GetKind() == "Secret")
+
+	switch {
+	case live == nil:
+		result.Action = "created"
+	case len(result.Changes) == 0:
+		result.Action = "unchanged"
+	default:
+		result.Action = "configured"
+	}
+	return result, nil
+}
+
+// fields maintained by the API server that are not part of a meaningful diff
+var ignoredDiffPaths = []string{
+	"metadata.managedFields",
+	"metadata.resourceVersion",
+	"metadata.generation",
+	"metadata.uid",
+	"metadata.creationTimestamp",
+	"metadata.annotations.kubectl.kubernetes.io/last-applied-configuration",
+	"status",
+}
+
+// diffObjects returns the leaf fields that differ between two objects. Secret values
+// are redacted so that they never reach the caller.
+func diffObjects(before, after map[string]any, redact bool) []FieldChange {
+	old := map[string]any{}
+	flatten("", before, old)
+	current := map[string]any{}
+	flatten("", after, current)
+
+	paths := map[string]bool{}
+	for p := range old {
+		paths[p] = true
+	}
+	for p := range current {
+		paths[p] = true
+	}
+
+	var changes []FieldChange
+	for p := range paths {
+		if ignoredPath(p) {
+			continue
+		}
+		o, n := old[p], current[p]
+		if reflect.DeepEqual(o, n) {
+			continue
+		}
+		if redact && (strings.HasPrefix(p, "data.") || strings.HasPrefix(p, "stringData.")) {
+			o, n = redacted(o), redacted(n)
+		}
+		changes = append(changes, FieldChange{Path: p, Old: o, New: n})
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+func flatten(prefix string, value any, out map[string]any) {
+	switch v := value.(type) {
+	case map[string]any:
+		if len(v) == 0 && prefix != "" {
+			out[prefix] = v
+		}
+		for key, child := range v {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			flatten(path, child, out)
+		}
+	case []any:
+		if len(v) == 0 {
+			out[prefix] = v
+		}
+		for i, child := range v {
+			flatten(fmt.Sprintf("%s[%d]", prefix, i), child, out)
+		}
+	default:
+		if prefix != "" {
+			out[prefix] = v
+		}
+	}
+}
+
+func ignoredPath(path string) bool {
+	for _, ignored := range ignoredDiffPaths {
+		if path == ignored || strings.HasPrefix(path, ignored+".") || strings.HasPrefix(path, ignored+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+func redacted(value any) any {
+	if value == nil {
+		return nil
+	}
+	return "<redacted>"
+}
@@ -0,0 +1,96 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	DefaultCertExpiryDays = 30
+)
+
+type ExpiringCertificate struct {
+	Namespace string   `json:"namespace"`
+	Secret    string   `json:"secret"`
+	Ingresses []string `json:"ingresses"`
+	CertificateInfo
+}
+
+type CertScanReport struct {
+	WithinDays int                   `json:"withinDays"`
+	Scanned    int                   `json:"scannedSecrets"`
+	Expiring   []ExpiringCertificate `json:"expiring"`
+	Problems   []string              `json:"problems,omitempty"`
+}
+
+// ScanIngressCertificates parses the TLS secrets referenced by ingresses (in a namespace,
+// or cluster-wide when namespace is empty) and lists certificates expiring within days
+func ScanIngressCertificates(namespace string, days int) (*CertScanReport, error) {
+	if days <= 0 {
+		days = DefaultCertExpiryDays
+	}
+
+	ingresses, err := GetIngresses(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	// namespace/secret -> referencing ingresses
+	references := map[string][]string{}
+	for _, ing := range ingresses {
+		for _, tls := range ing.TLS {
+			if tls.SecretName == "" {
+				continue
+			}
+			key := ing.Namespace + "/" + tls.SecretName
+			references[key] = append(references[key], ing.Name)
+		}
+	}
+
+	report := &CertScanReport{WithinDays: days}
+	for key, ingressNames := range references {
+		ns, name := splitKey(key)
+
+		secret, err := clientset.CoreV1().Secrets(ns).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			report.Problems = append(report.Problems, fmt.Sprintf("secret %s referenced by %v: %v", key, ingressNames, err))
+			continue
+		}
+		if secret.Type != corev1.SecretTypeTLS {
+			report.Problems = append(report.Problems, fmt.Sprintf("secret %s has type %s, expected %s", key, secret.Type, corev1.SecretTypeTLS))
+			continue
+		}
+		report.Scanned++
+
+		certs := parseCertificates(secret.Data[corev1.TLSCertKey])
+		if len(certs) == 0 {
+			report.Problems = append(report.Problems, fmt.Sprintf("secret %s has no valid certificate in %s", key, corev1.TLSCertKey))
+			continue
+		}
+
+		// the first certificate of the bundle is the leaf served to clients
+		if certs[0].DaysLeft <= days {
+			report.Expiring = append(report.Expiring, ExpiringCertificate{
+				Namespace:       ns,
+				Secret:          name,
+				Ingresses:       ingressNames,
+				CertificateInfo: certs[0],
+			})
+		}
+	}
+
+	sort.Slice(report.Expiring, func(i, j int) bool { return report.Expiring[i].DaysLeft < report.Expiring[j].DaysLeft })
+	sort.Strings(report.Problems)
+
+	return report, nil
+}
+
+func splitKey(key string) (string, string) {
+	namespace, name, _ := strings.Cut(key, "/")
+	return namespace, name
+}
@@ -0,0 +1,82 @@
+package kube
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func tlsIngress(name string, secrets ...string) *networkingv1.Ingress {
+	ing := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"}}
+	for _, secret := range secrets {
+		ing.Spec.TLS = append(ing.Spec.TLS, networkingv1.IngressTLS{Hosts: []string{name + ".example.com"}, SecretName: secret})
+	}
+	return ing
+}
+
+func tlsSecret(name string, secretType corev1.SecretType, cert []byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"},
+		Type:       secretType,
+		Data:       map[string][]byte{corev1.TLSCertKey: cert},
+	}
+}
+
+func TestScanIngressCertificates(t *testing.T) {
+	fakeClientset(t,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		tlsSecret("cart-tls", corev1.SecretTypeTLS, selfSignedPEM(t, "cart.example.com", 10*24*time.Hour)),
+		tlsSecret("shop-tls", corev1.SecretTypeTLS, selfSignedPEM(t, "shop.example.com", 300*24*time.Hour)),
+		tlsSecret("opaque-tls", corev1.SecretTypeOpaque, selfSignedPEM(t, "admin.example.com", time.Hour)),
+		tlsSecret("broken-tls", corev1.SecretTypeTLS, []byte("not a certificate")),
+	)
+	fakeDynamicClient(t,
+		tlsIngress("cart", "cart-tls"),
+		tlsIngress("cart-admin", "cart-tls", "opaque-tls"),
+		tlsIngress("shop", "shop-tls", "missing-tls"),
+		tlsIngress("legacy", "broken-tls"),
+		// TLS without a secret, terminated by the ingress controller default certificate
+		tlsIngress("default-cert", ""),
+	)
+
+	report, err := ScanIngressCertificates("shop", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.WithinDays != DefaultCertExpiryDays || report.Scanned != 3 {
+		t.Errorf("withinDays = %d, scanned = %d, want %d and 3", report.WithinDays, report.Scanned, DefaultCertExpiryDays)
+	}
+
+	if len(report.Expiring) != 1 {
+		t.Fatalf("expiring = %+v, want only cart-tls", report.Expiring)
+	}
+	expiring := report.Expiring[0]
+	sort.Strings(expiring.Ingresses)
+	if expiring.Secret != "cart-tls" || strings.Join(expiring.Ingresses, ",") != "cart,cart-admin" || expiring.DaysLeft > 10 {
+		t.Errorf("expiring = %s referenced by %v, %d days left", expiring.Secret, expiring.Ingresses, expiring.DaysLeft)
+	}
+
+	for _, want := range []string{"shop/broken-tls has no valid certificate", "shop/missing-tls referenced by [shop]", "shop/opaque-tls has type Opaque"} {
+		found := false
+		for _, problem := range report.Problems {
+			found = found || strings.Contains(problem, want)
+		}
+		if !found {
+			t.Errorf("problems = %v, missing %q", report.Problems, want)
+		}
+	}
+	if len(report.Problems) != 3 {
+		t.Errorf("problems = %v, want 3", report.Problems)
+	}
+
+	// a wider window also reports the certificate valid for 300 days
+	if report, err := ScanIngressCertificates("shop", 365); err != nil || len(report.Expiring) != 2 || report.Expiring[0].Secret != "cart-tls" {
+		t.Errorf("within 365 days = %+v, %v, want both certificates sorted by days left", report, err)
+	}
+}
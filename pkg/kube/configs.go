@@ -0,0 +1,178 @@
+package kube
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ConfigMap values longer than this are truncated before being returned
+	maxConfigValueLength = 256
+)
+
+type ConfigMapInfo struct {
+	Name string            `json:"name"`
+	Age  string            `json:"age"`
+	Data map[string]string `json:"data,omitempty"`
+	// binary values are never returned, only their size
+	BinaryData map[string]int `json:"binaryData,omitempty"`
+}
+
+type SecretInfo struct {
+	Name         string            `json:"name"`
+	Type         string            `json:"type"`
+	Age          string            `json:"age"`
+	Keys         []SecretKey       `json:"keys"`
+	Certificates []CertificateInfo `json:"certificates,omitempty"`
+}
+
+type SecretKey struct {
+	Name string `json:"name"`
+	Size int    `json:"size"`
+}
+
+type CertificateInfo struct {
+	Subject     string    `json:"subject"`
+	Issuer      string    `json:"issuer"`
+	DNSNames    []string  `json:"dnsNames,omitempty"`
+	IPAddresses []string  `json:"ipAddresses,omitempty"`
+	NotBefore   time.Time `json:"notBefore"`
+	NotAfter    time.Time `json:"notAfter"`
+	DaysLeft    int       `json:"daysLeft"`
+}
+
+// GetConfigMaps returns the (truncated) data of one ConfigMap, or of every ConfigMap when name is empty
+func GetConfigMaps(namespace, name string) ([]ConfigMapInfo, error) {
+	var items []corev1.ConfigMap
+	if name != "" {
+		cm, err := clientset.CoreV1().ConfigMaps(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get configmap '%s': %v", name, err)
+		}
+		items = append(items, *cm)
+	} else {
+		list, err := clientset.CoreV1().ConfigMaps(namespace).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list configmaps: %v", err)
+		}
+		items = list.Items
+	}
+
+	var configMaps []ConfigMapInfo
+	for _, cm := range items {
+		info := ConfigMapInfo{Name: cm.Name, Age: age(cm.CreationTimestamp)}
+
+		if len(cm.Data) > 0 {
+			info.Data = make(map[string]string)
+			for key, value := range cm.Data {
+				info.Data[key] = truncateValue(value)
+			}
+		}
+
+		if len(cm.BinaryData) > 0 {
+			info.BinaryData = make(map[string]int)
+			for key, value := range cm.BinaryData {
+				info.BinaryData[key] = len(value)
+			}
+		}
+
+		configMaps = append(configMaps, info)
+	}
+	return configMaps, nil
+}
+
+// truncateValue shortens values longer than maxConfigValueLength bytes without cutting
+// a multi-byte UTF-8 character in two
+func truncateValue(value string) string {
+	if len(value) <= maxConfigValueLength {
+		return value
+	}
+	end := maxConfigValueLength
+	for end > 0 && !utf8.RuneStart(value[end]) {
+		end--
+	}
+	return fmt.Sprintf("%s... (truncated, %d bytes)", value[:end], len(value))
+}
+
+// GetSecrets returns the metadata of one Secret, or of every Secret when name is empty.
+// Secret values are never returned: only key names and sizes, and for TLS secrets the
+// parsed certificate details.
+func GetSecrets(namespace, name string) ([]SecretInfo, error) {
+	var items []corev1.Secret
+	if name != "" {
+		s, err := clientset.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get secret '%s': %v", name, err)
+		}
+		items = append(items, *s)
+	} else {
+		list, err := clientset.CoreV1().Secrets(namespace).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list secrets: %v", err)
+		}
+		items = list.Items
+	}
+
+	var secrets []SecretInfo
+	for _, s := range items {
+		secrets = append(secrets, redactSecret(&s))
+	}
+	return secrets, nil
+}
+
+func redactSecret(s *corev1.Secret) SecretInfo {
+	info := SecretInfo{Name: s.Name, Type: string(s.Type), Age: age(s.CreationTimestamp)}
+
+	for key, value := range s.Data {
+		info.Keys = append(info.Keys, SecretKey{Name: key, Size: len(value)})
+	}
+	sort.Slice(info.Keys, func(i, j int) bool { return info.Keys[i].Name < info.Keys[j].Name })
+
+	if crt, ok := s.Data[corev1.TLSCertKey]; ok {
+		// the certificate is public information, the private key is never parsed
+		info.Certificates = parseCertificates(crt)
+	}
+	return info
+}
+
+// parseCertificates decodes every certificate of a PEM bundle, skipping invalid blocks
+func parseCertificates(data []byte) []CertificateInfo {
+	var certs []CertificateInfo
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+
+		info := CertificateInfo{
+			Subject:   cert.Subject.String(),
+			Issuer:    cert.Issuer.String(),
+			DNSNames:  cert.DNSNames,
+			NotBefore: cert.NotBefore,
+			NotAfter:  cert.NotAfter,
+			DaysLeft:  int(time.Until(cert.NotAfter).Hours() / 24),
+		}
+		for _, ip := range cert.IPAddresses {
+			info.IPAddresses = append(info.IPAddresses, ip.String())
+		}
+		certs = append(certs, info)
+	}
+	return certs
+}
@@ -0,0 +1,268 @@
+package kube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// nested spec/status values larger than this are summarized instead of returned verbatim
+	maxFieldSize = 512
+	maxEvents    = 10
+)
+
+type ObjectDescription struct {
+	Kind            string            `json:"kind"`
+	APIVersion      string            `json:"apiVersion"`
+	Name            string            `json:"name"`
+	Namespace       string            `json:"namespace,omitempty"`
+	Age             string            `json:"age"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	OwnerReferences []OwnerReference  `json:"ownerReferences,omitempty"`
+	Containers      []ContainerInfo   `json:"containers,omitempty"`
+	Spec            map[string]any    `json:"spec,omitempty"`
+	DataKeys        []string          `json:"dataKeys,omitempty"`
+	Status          map[string]any    `json:"status,omitempty"`
+	Conditions      []Condition       `json:"conditions,omitempty"`
+	Events          []EventInfo       `json:"events,omitempty"`
+}
+
+type OwnerReference struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+type ContainerInfo struct {
+	Name  string `json:"name"`
+	Image string `json:"image"`
+}
+
+type Condition struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type EventInfo struct {
+	Type    string `json:"type"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+	Count   int32  `json:"count"`
+	Age     string `json:"age"`
+}
+
+// resolveKind maps a user supplied kind ("deploy", "Ingress", "pods") to its REST mapping
+func resolveKind(kind string) (*meta.RESTMapping, error) {
+	resource := schema.GroupVersionResource{Resource: strings.ToLower(kind)}
+	gvk, err := restMapper.KindFor(resource)
+	if err != nil {
+		// the cached discovery may be stale (e.g. a CRD was installed after startup)
+		restMapper.Reset()
+		gvk, err = restMapper.KindFor(resource)
+	}
+	if err != nil {
+		// the mapper does not know short names such as deploy, svc or cm
+		expanded, found := expandShortName(resource.Resource)
+		if !found {
+			return nil, fmt.Errorf("unknown kind '%s': %v", kind, err)
+		}
+		if gvk, err = restMapper.KindFor(expanded); err != nil {
+			return nil, fmt.Errorf("unknown kind '%s': %v", kind, err)
+		}
+	}
+
+	mapping, err := restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to map kind '%s': %v", kind, err)
+	}
+	return mapping, nil
+}
+
+// expandShortName returns the resource with the given short name, as advertised by discovery
+func expandShortName(shortName string) (schema.GroupVersionResource, bool) {
+	// partial results are returned when some API groups are unavailable
+	lists, err := clientset.Discovery().ServerPreferredResources()
+	if len(lists) == 0 && err != nil {
+		return schema.GroupVersionResource{}, false
+	}
+
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, r := range list.APIResources {
+			for _, short := range r.ShortNames {
+				if short == shortName {
+					return gv.WithResource(r.Name), true
+				}
+			}
+		}
+	}
+	return schema.GroupVersionResource{}, false
+}
+
+// DescribeObject returns a condensed, kubectl describe like view of any object
+func DescribeObject(namespace, kind, name string) (*ObjectDescription, error) {
+	mapping, err := resolveKind(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	var obj *unstructured.Unstructured
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		obj, err = dynamicClient.Resource(mapping.Resource).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	} else {
+		namespace = ""
+		obj, err = dynamicClient.Resource(mapping.Resource).Get(context.TODO(), name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s '%s': %v", mapping.GroupVersionKind.Kind, name, err)
+	}
+
+	desc := &ObjectDescription{
+		Kind:       obj.GetKind(),
+		APIVersion: obj.GetAPIVersion(),
+		Name:       obj.GetName(),
+		Namespace:  obj.GetNamespace(),
+		Age:        age(obj.GetCreationTimestamp()),
+		Labels:     obj.GetLabels(),
+	}
+
+	for _, ref := range obj.GetOwnerReferences() {
+		desc.OwnerReferences = append(desc.OwnerReferences, OwnerReference{Kind: ref.Kind, Name: ref.Name})
+	}
+
+	if spec, ok := obj.Object["spec"].(map[string]any); ok {
+		desc.Containers = containersOf(spec)
+		desc.Spec = condense(spec)
+	}
+
+	// only the keys of ConfigMaps/Secrets are reported, values never leave the cluster
+	for _, field := range []string{"data", "stringData", "binaryData"} {
+		if data, ok := obj.Object[field].(map[string]any); ok {
+			for key := range data {
+				desc.DataKeys = append(desc.DataKeys, key)
+			}
+		}
+	}
+	sort.Strings(desc.DataKeys)
+
+	if status, ok := obj.Object["status"].(map[string]any); ok {
+		desc.Conditions = conditionsOf(status)
+		delete(status, "conditions")
+		desc.Status = condense(status)
+	}
+
+	desc.Events, err = GetEvents(namespace, desc.Kind, desc.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return desc, nil
+}
+
+// GetEvents returns the most recent events recorded against an object
+func GetEvents(namespace, kind, name string) ([]EventInfo, error) {
+	selector := fmt.Sprintf("involvedObject.kind=%s,involvedObject.name=%s", kind, name)
+	list, err := clientset.CoreV1().Events(namespace).List(context.TODO(), metav1.ListOptions{FieldSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %v", err)
+	}
+
+	items := list.Items
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].LastTimestamp.After(items[j].LastTimestamp.Time)
+	})
+	if len(items) > maxEvents {
+		items = items[:maxEvents]
+	}
+
+	var events []EventInfo
+	for _, e := range items {
+		events = append(events, EventInfo{
+			Type:    e.Type,
+			Reason:  e.Reason,
+			Message: e.Message,
+			Count:   e.Count,
+			Age:     age(e.LastTimestamp),
+		})
+	}
+	return events, nil
+}
+
+// containersOf extracts the containers of a pod spec or of a workload's pod template
+func containersOf(spec map[string]any) []ContainerInfo {
+	containers, found, _ := unstructured.NestedSlice(spec, "containers")
+	if !found {
+		containers, found, _ = unstructured.NestedSlice(spec, "template", "spec", "containers")
+	}
+	if !found {
+		containers, _, _ = unstructured.NestedSlice(spec, "jobTemplate", "spec", "template", "spec", "containers")
+	}
+
+	var result []ContainerInfo
+	for _, c := range containers {
+		if m, ok := c.(map[string]any); ok {
+			name, _ := m["name"].(string)
+			image, _ := m["image"].(string)
+			result = append(result, ContainerInfo{Name: name, Image: image})
+		}
+	}
+	return result
+}
+
+func conditionsOf(status map[string]any) []Condition {
+	items, _, _ := unstructured.NestedSlice(status, "conditions")
+
+	var conditions []Condition
+	for _, item := range items {
+		if m, ok := item.(map[string]any); ok {
+			c := Condition{}
+			c.Type, _ = m["type"].(string)
+			c.Status, _ = m["status"].(string)
+			c.Reason, _ = m["reason"].(string)
+			c.Message, _ = m["message"].(string)
+			conditions = append(conditions, c)
+		}
+	}
+	return conditions
+}
+
+// condense keeps scalars and small nested values, and summarizes the rest
+func condense(fields map[string]any) map[string]any {
+	result := make(map[string]any)
+	for key, value := range fields {
+		switch v := value.(type) {
+		case map[string]any, []any:
+			raw, err := json.Marshal(v)
+			if err != nil || len(raw) > maxFieldSize {
+				result[key] = summarize(v)
+			} else {
+				result[key] = v
+			}
+		default:
+			result[key] = v
+		}
+	}
+	return result
+}
+
+func summarize(value any) string {
+	switch v := value.(type) {
+	case map[string]any:
+		return fmt.Sprintf("<object with %d fields>", len(v))
+	case []any:
+		return fmt.Sprintf("<list of %d items>", len(v))
+	}
+	return "<omitted>"
+}
@@ -0,0 +1,260 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// number of log lines fetched from a failed container or its previous instance
+	previousLogLines = 20
+)
+
+type Finding struct {
+	Pod             string   `json:"pod,omitempty"`
+	Container       string   `json:"container,omitempty"`
+	ProbableCause   string   `json:"probableCause"`
+	Evidence        []string `json:"evidence"`
+	SuggestedAction string   `json:"suggestedAction"`
+}
+
+type Diagnosis struct {
+	Deployment string    `json:"deployment"`
+	Namespace  string    `json:"namespace"`
+	Replicas   string    `json:"replicas"`
+	Findings   []Finding `json:"findings"`
+}
+
+// DiagnoseWorkload inspects the pods of a deployment and reports the probable causes of failures
+func DiagnoseWorkload(namespace, deployment string) (*Diagnosis, error) {
+	d, err := clientset.AppsV1().Deployments(namespace).Get(context.TODO(), deployment, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment: %v", err)
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(d.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector on deployment '%s': %v", deployment, err)
+	}
+
+	pods, err := clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %v", err)
+	}
+
+	var desired int32 = 1
+	if d.Spec.Replicas != nil {
+		desired = *d.Spec.Replicas
+	}
+
+	diagnosis := &Diagnosis{
+		Deployment: d.Name,
+		Namespace:  namespace,
+		Replicas:   fmt.Sprintf("%d/%d ready", d.Status.ReadyReplicas, desired),
+	}
+
+	for _, c := range d.Status.Conditions {
+		if c.Type == "ReplicaFailure" && c.Status == corev1.ConditionTrue {
+			diagnosis.Findings = append(diagnosis.Findings, Finding{
+				ProbableCause:   "Pods cannot be created for the deployment",
+				Evidence:        []string{fmt.Sprintf("%s: %s", c.Reason, c.Message)},
+				SuggestedAction: "Check ResourceQuota, LimitRange and admission policies in the namespace",
+			})
+		}
+	}
+
+	if len(pods.Items) == 0 && desired > 0 {
+		diagnosis.Findings = append(diagnosis.Findings, Finding{
+			ProbableCause:   "No pods match the deployment selector",
+			Evidence:        []string{fmt.Sprintf("selector: %s", selector.String())},
+			SuggestedAction: "Check the ReplicaSet events of the deployment and the namespace quota",
+		})
+	}
+
+	// usage is best effort, metrics-server may not be installed
+	usage := map[string]PodCpuMemory{}
+	if metrics, err := GetPodCpuMemory(namespace); err == nil {
+		for _, m := range metrics {
+			usage[m.Name] = m
+		}
+	}
+
+	for _, pod := range pods.Items {
+		diagnosis.Findings = append(diagnosis.Findings, diagnosePod(&pod, usage)...)
+	}
+
+	return diagnosis, nil
+}
+
+func diagnosePod(pod *corev1.Pod, usage map[string]PodCpuMemory) []Finding {
+	var findings []Finding
+
+	events, _ := GetEvents(pod.Namespace, "Pod", pod.Name)
+	var warnings []string
+	for _, e := range events {
+		if e.Type == corev1.EventTypeWarning {
+			warnings = append(warnings, fmt.Sprintf("%s: %s (x%d, %s ago)", e.Reason, e.Message, e.Count, e.Age))
+		}
+	}
+
+	if pod.Status.Phase == corev1.PodPending {
+		for _, c := range pod.Status.Conditions {
+			if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse {
+				findings = append(findings, Finding{
+					Pod:             pod.Name,
+					ProbableCause:   "Pod cannot be scheduled",
+					Evidence:        append([]string{fmt.Sprintf("%s: %s", c.Reason, c.Message)}, warnings...),
+					SuggestedAction: "Check node capacity, taints/tolerations, node selectors and the pod's resource requests",
+				})
+			}
+		}
+	}
+
+	// init containers run one after the other before the containers, a failing one
+	// blocks the pod in Init:CrashLoopBackOff, Init:ImagePullBackOff or Init:Error
+	var statuses []corev1.ContainerStatus
+	init := map[string]bool{}
+	for _, cs := range pod.Status.InitContainerStatuses {
+		statuses = append(statuses, cs)
+		init[cs.Name] = true
+	}
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+
+	for _, cs := range statuses {
+		finding := classifyContainer(pod, &cs, init[cs.Name])
+		if finding.ProbableCause == "" {
+			continue
+		}
+
+		// logs cost an API call, they are only fetched for the containers with a finding
+		if terminated := cs.State.Terminated; terminated != nil && terminated.ExitCode != 0 {
+			if logs, err := containerLogs(pod, cs.Name, false); err == nil && logs != "" {
+				finding.Evidence = append(finding.Evidence, "logs:\n"+logs)
+			}
+		} else if cs.LastTerminationState.Terminated != nil {
+			if logs, err := containerLogs(pod, cs.Name, true); err == nil && logs != "" {
+				finding.Evidence = append(finding.Evidence, "previous logs:\n"+logs)
+			}
+		}
+
+		finding.Evidence = append(finding.Evidence, fmt.Sprintf("restarts: %d", cs.RestartCount))
+		if u, ok := usage[pod.Name]; ok {
+			for _, c := range u.Containers {
+				if c.Name != cs.Name {
+					continue
+				}
+				evidence := fmt.Sprintf("container usage: CPU %s, Memory %s", c.CPU, c.Memory)
+				if c.MemoryLimit != "" {
+					evidence += fmt.Sprintf(" (%.1f%% of the %s memory limit)", c.MemoryLimitPercent, c.MemoryLimit)
+				}
+				finding.Evidence = append(finding.Evidence, evidence)
+			}
+		}
+		finding.Evidence = append(finding.Evidence, warnings...)
+		findings = append(findings, finding)
+	}
+
+	return findings
+}
+
+// classifyContainer returns the probable cause of a failing container from its status,
+// the finding has no cause when the container is healthy
+func classifyContainer(pod *corev1.Pod, cs *corev1.ContainerStatus, init bool) Finding {
+	finding := Finding{Pod: pod.Name, Container: cs.Name}
+	kind := "Container"
+	if init {
+		kind = "Init container"
+	}
+
+	if waiting := cs.State.Waiting; waiting != nil {
+		finding.Evidence = append(finding.Evidence, fmt.Sprintf("state: Waiting (%s) %s", waiting.Reason, waiting.Message))
+
+		switch waiting.Reason {
+		case "ImagePullBackOff", "ErrImagePull", "InvalidImageName":
+			finding.ProbableCause = fmt.Sprintf("Image '%s' cannot be pulled", containerImage(pod, cs.Name))
+			finding.SuggestedAction = "Verify the image name and tag exist in the registry and that imagePullSecrets grant access"
+		case "CreateContainerConfigError", "CreateContainerError":
+			finding.ProbableCause = kind + " configuration is invalid"
+			finding.SuggestedAction = "Check that referenced ConfigMaps, Secrets and keys exist in the namespace"
+		case "CrashLoopBackOff":
+			if init {
+				finding.ProbableCause = "Init container keeps failing, the containers of the pod cannot start"
+				finding.SuggestedAction = "Inspect the previous init container logs for the error"
+			} else {
+				finding.ProbableCause = "Container keeps crashing after start"
+				finding.SuggestedAction = "Inspect the previous container logs for the startup error"
+			}
+		}
+	}
+
+	// with restartPolicy Never a failed init container stays terminated (Init:Error)
+	if terminated := cs.State.Terminated; init && terminated != nil && terminated.ExitCode != 0 {
+		finding.Evidence = append(finding.Evidence, fmt.Sprintf("state: Terminated (%s) exit code %d", terminated.Reason, terminated.ExitCode))
+		finding.ProbableCause = "Init container exited with an error, the containers of the pod cannot start"
+		finding.SuggestedAction = "Inspect the init container logs for the error"
+	}
+
+	if last := cs.LastTerminationState.Terminated; last != nil {
+		finding.Evidence = append(finding.Evidence, fmt.Sprintf("last termination: %s (exit code %d) at %s", last.Reason, last.ExitCode, last.FinishedAt.Format("2006-01-02 15:04:05")))
+
+		if last.Reason == "OOMKilled" {
+			finding.ProbableCause = kind + " is killed for exceeding its memory limit"
+			finding.SuggestedAction = fmt.Sprintf("Raise the memory limit (currently %s) or reduce the application's memory usage", containerLimit(pod, cs.Name, corev1.ResourceMemory))
+		} else if finding.ProbableCause == "" && cs.RestartCount > 0 && cs.State.Running == nil {
+			finding.ProbableCause = kind + " exited with an error"
+			finding.SuggestedAction = "Inspect the previous container logs for the failure"
+		}
+	}
+
+	if finding.ProbableCause == "" && !init && !cs.Ready && pod.Status.Phase == corev1.PodRunning && cs.State.Running != nil {
+		finding.ProbableCause = "Container is running but not ready"
+		finding.SuggestedAction = "Check the readiness probe configuration and the application's health endpoint"
+	}
+
+	return finding
+}
+
+// containerLogs returns the last lines of the logs of a container, of its previous
+// instance when previous is set
+func containerLogs(pod *corev1.Pod, container string, previous bool) (string, error) {
+	tail := int64(previousLogLines)
+	raw, err := clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: container,
+		Previous:  previous,
+		TailLines: &tail,
+	}).DoRaw(context.TODO())
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(raw)), nil
+}
+
+// podContainers returns the init containers and the containers of a pod
+func podContainers(pod *corev1.Pod) []corev1.Container {
+	return append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+}
+
+func containerImage(pod *corev1.Pod, name string) string {
+	for _, c := range podContainers(pod) {
+		if c.Name == name {
+			return c.Image
+		}
+	}
+	return ""
+}
+
+func containerLimit(pod *corev1.Pod, name string, resourceName corev1.ResourceName) string {
+	for _, c := range podContainers(pod) {
+		if c.Name != name {
+			continue
+		}
+		if q, ok := c.Resources.Limits[resourceName]; ok {
+			return q.String()
+		}
+	}
+	return "not set"
+}
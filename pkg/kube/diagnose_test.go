@@ -0,0 +1,132 @@
+package kube
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func waiting(reason string) corev1.ContainerState {
+	return corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason}}
+}
+
+func terminated(reason string, exitCode int32) corev1.ContainerState {
+	return corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: reason, ExitCode: exitCode}}
+}
+
+func running() corev1.ContainerState {
+	return corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+}
+
+func TestClassifyContainer(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "cart-6d9f7c-x2x4z", Namespace: "shop"},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "migrate", Image: "registry.example.com/cart-migrate:1.4"}},
+			Containers: []corev1.Container{{
+				Name:      "app",
+				Image:     "registry.example.com/cart:1.4",
+				Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")}},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+
+	tests := []struct {
+		name   string
+		status corev1.ContainerStatus
+		init   bool
+		// substrings of the probable cause and suggested action, no finding when empty
+		cause  string
+		action string
+	}{
+		{
+			name:   "image pull",
+			status: corev1.ContainerStatus{Name: "app", State: waiting("ImagePullBackOff")},
+			cause:  "Image 'registry.example.com/cart:1.4' cannot be pulled",
+			action: "imagePullSecrets",
+		},
+		{
+			name:   "missing configmap",
+			status: corev1.ContainerStatus{Name: "app", State: waiting("CreateContainerConfigError")},
+			cause:  "Container configuration is invalid",
+		},
+		{
+			name:   "crash loop",
+			status: corev1.ContainerStatus{Name: "app", State: waiting("CrashLoopBackOff"), LastTerminationState: terminated("Error", 1), RestartCount: 5},
+			cause:  "Container keeps crashing after start",
+		},
+		{
+			name:   "out of memory",
+			status: corev1.ContainerStatus{Name: "app", State: waiting("CrashLoopBackOff"), LastTerminationState: terminated("OOMKilled", 137), RestartCount: 3},
+			cause:  "Container is killed for exceeding its memory limit",
+			action: "currently 512Mi",
+		},
+		{
+			name:   "running but not ready",
+			status: corev1.ContainerStatus{Name: "app", State: running()},
+			cause:  "Container is running but not ready",
+		},
+		{
+			name:   "healthy",
+			status: corev1.ContainerStatus{Name: "app", State: running(), Ready: true},
+		},
+		{
+			name:   "healthy after a restart",
+			status: corev1.ContainerStatus{Name: "app", State: running(), Ready: true, LastTerminationState: terminated("Error", 1), RestartCount: 1},
+		},
+		{
+			name:   "Init:ImagePullBackOff",
+			status: corev1.ContainerStatus{Name: "migrate", State: waiting("ImagePullBackOff")},
+			init:   true,
+			cause:  "Image 'registry.example.com/cart-migrate:1.4' cannot be pulled",
+		},
+		{
+			name:   "Init:CrashLoopBackOff",
+			status: corev1.ContainerStatus{Name: "migrate", State: waiting("CrashLoopBackOff"), LastTerminationState: terminated("Error", 2), RestartCount: 4},
+			init:   true,
+			cause:  "Init container keeps failing",
+			action: "init container logs",
+		},
+		{
+			name:   "Init:Error",
+			status: corev1.ContainerStatus{Name: "migrate", State: terminated("Error", 1)},
+			init:   true,
+			cause:  "Init container exited with an error",
+		},
+		{
+			name:   "init container completed",
+			status: corev1.ContainerStatus{Name: "migrate", State: terminated("Completed", 0), Ready: true},
+			init:   true,
+		},
+		{
+			name:   "init container running",
+			status: corev1.ContainerStatus{Name: "migrate", State: running()},
+			init:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finding := classifyContainer(pod, &tt.status, tt.init)
+			if tt.cause == "" {
+				if finding.ProbableCause != "" {
+					t.Errorf("got finding %q for a healthy container", finding.ProbableCause)
+				}
+				return
+			}
+			if !strings.Contains(finding.ProbableCause, tt.cause) {
+				t.Errorf("probable cause = %q, want %q", finding.ProbableCause, tt.cause)
+			}
+			if !strings.Contains(finding.SuggestedAction, tt.action) {
+				t.Errorf("suggested action = %q, want %q", finding.SuggestedAction, tt.action)
+			}
+			if finding.Pod != pod.Name || finding.Container != tt.status.Name {
+				t.Errorf("finding of %s/%s, want %s/%s", finding.Pod, finding.Container, pod.Name, tt.status.Name)
+			}
+		})
+	}
+}
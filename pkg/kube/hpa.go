@@ -0,0 +1,149 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type HPAInfo struct {
+	Name            string      `json:"name"`
+	Target          string      `json:"target"`
+	MinReplicas     int32       `json:"minReplicas"`
+	MaxReplicas     int32       `json:"maxReplicas"`
+	CurrentReplicas int32       `json:"currentReplicas"`
+	DesiredReplicas int32       `json:"desiredReplicas"`
+	Metrics         []HPAMetric `json:"metrics"`
+	Conditions      []Condition `json:"conditions,omitempty"`
+	LastScaleTime   string      `json:"lastScaleTime,omitempty"`
+}
+
+type HPAMetric struct {
+	Name    string `json:"name"`
+	Current string `json:"current"`
+	Target  string `json:"target"`
+}
+
+// GetHPAs lists the HorizontalPodAutoscalers of a namespace using autoscaling/v2
+func GetHPAs(namespace string) ([]HPAInfo, error) {
+	_, err := clientset.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("namespace '%s' not found: %v", namespace, err)
+	}
+
+	list, err := clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list horizontal pod autoscalers: %v", err)
+	}
+
+	var hpas []HPAInfo
+	for _, hpa := range list.Items {
+		var minReplicas int32 = 1
+		if hpa.Spec.MinReplicas != nil {
+			minReplicas = *hpa.Spec.MinReplicas
+		}
+
+		info := HPAInfo{
+			Name:            hpa.Name,
+			Target:          fmt.Sprintf("%s/%s", hpa.Spec.ScaleTargetRef.Kind, hpa.Spec.ScaleTargetRef.Name),
+			MinReplicas:     minReplicas,
+			MaxReplicas:     hpa.Spec.MaxReplicas,
+			CurrentReplicas: hpa.Status.CurrentReplicas,
+			DesiredReplicas: hpa.Status.DesiredReplicas,
+		}
+		if hpa.Status.LastScaleTime != nil {
+			info.LastScaleTime = age(*hpa.Status.LastScaleTime) + " ago"
+		}
+
+		// spec and status metrics are reported in the same order
+		for i, spec := range hpa.Spec.Metrics {
+			metric := HPAMetric{Name: metricName(spec), Current: "<unknown>", Target: metricTarget(spec)}
+			if i < len(hpa.Status.CurrentMetrics) {
+				metric.Current = metricCurrent(hpa.Status.CurrentMetrics[i])
+			}
+			info.Metrics = append(info.Metrics, metric)
+		}
+
+		for _, c := range hpa.Status.Conditions {
+			info.Conditions = append(info.Conditions, Condition{
+				Type:    string(c.Type),
+				Status:  string(c.Status),
+				Reason:  c.Reason,
+				Message: c.Message,
+			})
+		}
+
+		hpas = append(hpas, info)
+	}
+	return hpas, nil
+}
+
+func metricName(spec autoscalingv2.MetricSpec) string {
+	switch spec.Type {
+	case autoscalingv2.ResourceMetricSourceType:
+		return fmt.Sprintf("resource %s", spec.Resource.Name)
+	case autoscalingv2.ContainerResourceMetricSourceType:
+		return fmt.Sprintf("resource %s of container %s", spec.ContainerResource.Name, spec.ContainerResource.Container)
+	case autoscalingv2.PodsMetricSourceType:
+		return fmt.Sprintf("pods metric %s", spec.Pods.Metric.Name)
+	case autoscalingv2.ObjectMetricSourceType:
+		return fmt.Sprintf("object metric %s on %s/%s", spec.Object.Metric.Name, spec.Object.DescribedObject.Kind, spec.Object.DescribedObject.Name)
+	case autoscalingv2.ExternalMetricSourceType:
+		return fmt.Sprintf("external metric %s", spec.External.Metric.Name)
+	}
+	return string(spec.Type)
+}
+
+func metricTarget(spec autoscalingv2.MetricSpec) string {
+	var target autoscalingv2.MetricTarget
+	switch spec.Type {
+	case autoscalingv2.ResourceMetricSourceType:
+		target = spec.Resource.Target
+	case autoscalingv2.ContainerResourceMetricSourceType:
+		target = spec.ContainerResource.Target
+	case autoscalingv2.PodsMetricSourceType:
+		target = spec.Pods.Target
+	case autoscalingv2.ObjectMetricSourceType:
+		target = spec.Object.Target
+	case autoscalingv2.ExternalMetricSourceType:
+		target = spec.External.Target
+	}
+
+	switch {
+	case target.AverageUtilization != nil:
+		return fmt.Sprintf("%d%% (average utilization)", *target.AverageUtilization)
+	case target.AverageValue != nil:
+		return fmt.Sprintf("%s (average value)", target.AverageValue.String())
+	case target.Value != nil:
+		return target.Value.String()
+	}
+	return "<unknown>"
+}
+
+func metricCurrent(status autoscalingv2.MetricStatus) string {
+	var current autoscalingv2.MetricValueStatus
+	switch status.Type {
+	case autoscalingv2.ResourceMetricSourceType:
+		current = status.Resource.Current
+	case autoscalingv2.ContainerResourceMetricSourceType:
+		current = status.ContainerResource.Current
+	case autoscalingv2.PodsMetricSourceType:
+		current = status.Pods.Current
+	case autoscalingv2.ObjectMetricSourceType:
+		current = status.Object.Current
+	case autoscalingv2.ExternalMetricSourceType:
+		current = status.External.Current
+	}
+
+	switch {
+	case current.AverageUtilization != nil:
+		return fmt.Sprintf("%d%%", *current.AverageUtilization)
+	case current.AverageValue != nil:
+		return current.AverageValue.String()
+	case current.Value != nil:
+		return current.Value.String()
+	}
+	return "<unknown>"
+}
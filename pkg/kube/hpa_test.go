@@ -0,0 +1,159 @@
+package kube
+
+import (
+	"reflect"
+	"testing"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestHPAMetrics(t *testing.T) {
+	utilization := int32(70)
+	currentUtilization := int32(85)
+	averageValue := resource.MustParse("100")
+	value := resource.MustParse("30")
+
+	tests := []struct {
+		name    string
+		spec    autoscalingv2.MetricSpec
+		status  autoscalingv2.MetricStatus
+		metric  string
+		target  string
+		current string
+	}{
+		{
+			name: "resource utilization",
+			spec: autoscalingv2.MetricSpec{Type: autoscalingv2.ResourceMetricSourceType, Resource: &autoscalingv2.ResourceMetricSource{
+				Name: corev1.ResourceCPU, Target: autoscalingv2.MetricTarget{AverageUtilization: &utilization},
+			}},
+			status: autoscalingv2.MetricStatus{Type: autoscalingv2.ResourceMetricSourceType, Resource: &autoscalingv2.ResourceMetricStatus{
+				Name: corev1.ResourceCPU, Current: autoscalingv2.MetricValueStatus{AverageUtilization: &currentUtilization},
+			}},
+			metric:  "resource cpu",
+			target:  "70% (average utilization)",
+			current: "85%",
+		},
+		{
+			name: "container resource",
+			spec: autoscalingv2.MetricSpec{Type: autoscalingv2.ContainerResourceMetricSourceType, ContainerResource: &autoscalingv2.ContainerResourceMetricSource{
+				Name: corev1.ResourceMemory, Container: "app", Target: autoscalingv2.MetricTarget{AverageUtilization: &utilization},
+			}},
+			metric: "resource memory of container app",
+			target: "70% (average utilization)",
+		},
+		{
+			name: "pods average value",
+			spec: autoscalingv2.MetricSpec{Type: autoscalingv2.PodsMetricSourceType, Pods: &autoscalingv2.PodsMetricSource{
+				Metric: autoscalingv2.MetricIdentifier{Name: "requests_per_second"}, Target: autoscalingv2.MetricTarget{AverageValue: &averageValue},
+			}},
+			status: autoscalingv2.MetricStatus{Type: autoscalingv2.PodsMetricSourceType, Pods: &autoscalingv2.PodsMetricStatus{
+				Metric: autoscalingv2.MetricIdentifier{Name: "requests_per_second"}, Current: autoscalingv2.MetricValueStatus{AverageValue: &averageValue},
+			}},
+			metric:  "pods metric requests_per_second",
+			target:  "100 (average value)",
+			current: "100",
+		},
+		{
+			name: "object value",
+			spec: autoscalingv2.MetricSpec{Type: autoscalingv2.ObjectMetricSourceType, Object: &autoscalingv2.ObjectMetricSource{
+				Metric:          autoscalingv2.MetricIdentifier{Name: "requests"},
+				DescribedObject: autoscalingv2.CrossVersionObjectReference{Kind: "Ingress", Name: "shop"},
+				Target:          autoscalingv2.MetricTarget{Value: &value},
+			}},
+			metric: "object metric requests on Ingress/shop",
+			target: "30",
+		},
+		{
+			name: "external",
+			spec: autoscalingv2.MetricSpec{Type: autoscalingv2.ExternalMetricSourceType, External: &autoscalingv2.ExternalMetricSource{
+				Metric: autoscalingv2.MetricIdentifier{Name: "queue_depth"}, Target: autoscalingv2.MetricTarget{Value: &value},
+			}},
+			status: autoscalingv2.MetricStatus{Type: autoscalingv2.ExternalMetricSourceType, External: &autoscalingv2.ExternalMetricStatus{
+				Metric: autoscalingv2.MetricIdentifier{Name: "queue_depth"}, Current: autoscalingv2.MetricValueStatus{Value: &value},
+			}},
+			metric:  "external metric queue_depth",
+			target:  "30",
+			current: "30",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := metricName(tt.spec); got != tt.metric {
+				t.Errorf("metricName() = %q, want %q", got, tt.metric)
+			}
+			if got := metricTarget(tt.spec); got != tt.target {
+				t.Errorf("metricTarget() = %q, want %q", got, tt.target)
+			}
+			if tt.current == "" {
+				return
+			}
+			if got := metricCurrent(tt.status); got != tt.current {
+				t.Errorf("metricCurrent() = %q, want %q", got, tt.current)
+			}
+		})
+	}
+}
+
+func TestGetHPAs(t *testing.T) {
+	utilization := int32(70)
+	currentUtilization := int32(85)
+	cpu := func(target autoscalingv2.MetricTarget) autoscalingv2.MetricSpec {
+		return autoscalingv2.MetricSpec{Type: autoscalingv2.ResourceMetricSourceType, Resource: &autoscalingv2.ResourceMetricSource{Name: corev1.ResourceCPU, Target: target}}
+	}
+
+	fakeClientset(t,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		&autoscalingv2.HorizontalPodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Name: "cart", Namespace: "shop"},
+			Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", Name: "cart"},
+				MaxReplicas:    10,
+				Metrics: []autoscalingv2.MetricSpec{
+					cpu(autoscalingv2.MetricTarget{AverageUtilization: &utilization}),
+					{Type: autoscalingv2.PodsMetricSourceType, Pods: &autoscalingv2.PodsMetricSource{Metric: autoscalingv2.MetricIdentifier{Name: "requests_per_second"}}},
+				},
+			},
+			Status: autoscalingv2.HorizontalPodAutoscalerStatus{
+				CurrentReplicas: 3,
+				DesiredReplicas: 4,
+				// the metrics server has not reported the pods metric yet
+				CurrentMetrics: []autoscalingv2.MetricStatus{{Type: autoscalingv2.ResourceMetricSourceType, Resource: &autoscalingv2.ResourceMetricStatus{
+					Name: corev1.ResourceCPU, Current: autoscalingv2.MetricValueStatus{AverageUtilization: &currentUtilization},
+				}}},
+				Conditions: []autoscalingv2.HorizontalPodAutoscalerCondition{{Type: autoscalingv2.ScalingActive, Status: corev1.ConditionTrue, Reason: "ValidMetricFound"}},
+			},
+		},
+	)
+
+	hpas, err := GetHPAs("shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hpas) != 1 {
+		t.Fatalf("got %d autoscalers, want 1", len(hpas))
+	}
+
+	hpa := hpas[0]
+	// minReplicas defaults to 1 when unset
+	if hpa.Target != "Deployment/cart" || hpa.MinReplicas != 1 || hpa.MaxReplicas != 10 || hpa.CurrentReplicas != 3 || hpa.DesiredReplicas != 4 {
+		t.Errorf("hpa = %+v", hpa)
+	}
+	want := []HPAMetric{
+		{Name: "resource cpu", Current: "85%", Target: "70% (average utilization)"},
+		{Name: "pods metric requests_per_second", Current: "<unknown>", Target: "<unknown>"},
+	}
+	if !reflect.DeepEqual(hpa.Metrics, want) {
+		t.Errorf("metrics = %+v, want %+v", hpa.Metrics, want)
+	}
+	if len(hpa.Conditions) != 1 || hpa.Conditions[0].Reason != "ValidMetricFound" {
+		t.Errorf("conditions = %+v", hpa.Conditions)
+	}
+
+	if _, err := GetHPAs("missing"); err == nil {
+		t.Errorf("GetHPAs() of a missing namespace succeeded")
+	}
+}
@@ -0,0 +1,196 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultRegistry = "docker.io"
+	// ByTag group of the images referenced by digest only
+	digestOnlyTag = "<digest>"
+)
+
+type ImageReport struct {
+	Images     []ImageInfo         `json:"images"`
+	ByRegistry map[string][]string `json:"byRegistry"`
+	ByTag      map[string][]string `json:"byTag"`
+	Findings   []string            `json:"findings,omitempty"`
+}
+
+type ImageInfo struct {
+	Image      string   `json:"image"`
+	Registry   string   `json:"registry"`
+	Repository string   `json:"repository"`
+	Tag        string   `json:"tag,omitempty"`
+	Digests    []string `json:"digests,omitempty"`
+	Workloads  []string `json:"workloads"`
+}
+
+// GetImageInventory lists the container images used per workload in a namespace, or
+// cluster-wide when namespace is empty, and flags :latest tags, digest drift between
+// sibling pods and images outside the allowed registries (no check when empty)
+func GetImageInventory(namespace string, allowedRegistries []string) (*ImageReport, error) {
+	pods, err := clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %v", err)
+	}
+
+	images := map[string]*ImageInfo{}
+	// workload/container (image) -> digest -> pods, to detect siblings running different
+	// digests of the same tag; a rolling update changing the image is not drift
+	digests := map[string]map[string][]string{}
+
+	for _, pod := range pods.Items {
+		workload := fmt.Sprintf("%s/%s", pod.Namespace, workloadOf(&pod))
+
+		containers := append([]corev1.Container{}, pod.Spec.InitContainers...)
+		containers = append(containers, pod.Spec.Containers...)
+		statuses := append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+		statuses = append(statuses, pod.Status.ContainerStatuses...)
+
+		for _, c := range containers {
+			info, ok := images[c.Image]
+			if !ok {
+				registry, repository, tag := parseImage(c.Image)
+				info = &ImageInfo{Image: c.Image, Registry: registry, Repository: repository, Tag: tag}
+				images[c.Image] = info
+			}
+			info.Workloads = appendUnique(info.Workloads, workload)
+
+			for _, cs := range statuses {
+				if cs.Name != c.Name || cs.ImageID == "" {
+					continue
+				}
+				digest := imageDigest(cs.ImageID)
+				info.Digests = appendUnique(info.Digests, digest)
+
+				key := fmt.Sprintf("%s/%s (%s)", workload, c.Name, c.Image)
+				if digests[key] == nil {
+					digests[key] = map[string][]string{}
+				}
+				digests[key][digest] = append(digests[key][digest], pod.Name)
+			}
+		}
+	}
+
+	report := &ImageReport{ByRegistry: map[string][]string{}, ByTag: map[string][]string{}}
+	for _, info := range images {
+		sort.Strings(info.Workloads)
+		report.Images = append(report.Images, *info)
+		report.ByRegistry[info.Registry] = append(report.ByRegistry[info.Registry], info.Image)
+		tag := info.Tag
+		if tag == "" {
+			tag = digestOnlyTag
+		}
+		report.ByTag[tag] = append(report.ByTag[tag], info.Image)
+
+		if info.Tag == "latest" {
+			report.Findings = append(report.Findings, fmt.Sprintf("image %s uses the :latest tag (workloads: %s)", info.Image, strings.Join(info.Workloads, ", ")))
+		}
+		if len(allowedRegistries) > 0 && !registryAllowed(info.Registry, allowedRegistries) {
+			report.Findings = append(report.Findings, fmt.Sprintf("image %s is pulled from registry %s, which is not in the allowed list", info.Image, info.Registry))
+		}
+	}
+
+	for key, byDigest := range digests {
+		if len(byDigest) < 2 {
+			continue
+		}
+		var parts []string
+		for digest, podNames := range byDigest {
+			parts = append(parts, fmt.Sprintf("%s on %s", shortDigest(digest), strings.Join(podNames, ", ")))
+		}
+		sort.Strings(parts)
+		report.Findings = append(report.Findings, fmt.Sprintf("container %s runs different digests across pods: %s", key, strings.Join(parts, "; ")))
+	}
+
+	sort.Slice(report.Images, func(i, j int) bool { return report.Images[i].Image < report.Images[j].Image })
+	for registry := range report.ByRegistry {
+		sort.Strings(report.ByRegistry[registry])
+	}
+	for tag := range report.ByTag {
+		sort.Strings(report.ByTag[tag])
+	}
+	sort.Strings(report.Findings)
+
+	return report, nil
+}
+
+// workloadOf names the top level controller of a pod, e.g. Deployment/cart
+func workloadOf(pod *corev1.Pod) string {
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		return "Pod/" + pod.Name
+	}
+
+	// ReplicaSets created by a Deployment are named <deployment>-<pod-template-hash>
+	if ref.Kind == "ReplicaSet" {
+		if hash, ok := pod.Labels["pod-template-hash"]; ok && strings.HasSuffix(ref.Name, "-"+hash) {
+			return "Deployment/" + strings.TrimSuffix(ref.Name, "-"+hash)
+		}
+	}
+	return ref.Kind + "/" + ref.Name
+}
+
+// parseImage splits an image reference into registry, repository and tag
+func parseImage(image string) (string, string, string) {
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		name = name[:i]
+	}
+
+	var tag string
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, tag = name[:i], name[i+1:]
+	} else if !strings.Contains(image, "@") {
+		tag = "latest"
+	}
+
+	registry := defaultRegistry
+	if first, rest, found := strings.Cut(name, "/"); found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		registry, name = first, rest
+	} else if !found {
+		name = "library/" + name
+	}
+	return registry, name, tag
+}
+
+// imageDigest extracts the sha256 digest from a container status imageID,
+// e.g. docker-pullable://nginx@sha256:abc... or sha256:abc...
+func imageDigest(imageID string) string {
+	if i := strings.Index(imageID, "sha256:"); i >= 0 {
+		return imageID[i:]
+	}
+	return imageID
+}
+
+func shortDigest(digest string) string {
+	if len(digest) > 19 {
+		return digest[:19]
+	}
+	return digest
+}
+
+func registryAllowed(registry string, allowed []string) bool {
+	for _, a := range allowed {
+		if strings.EqualFold(strings.TrimSpace(a), registry) {
+			return true
+		}
+	}
+	return false
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, v := range list {
+		if v == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
@@ -0,0 +1,179 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type JobInfo struct {
+	Name           string `json:"name"`
+	Owner          string `json:"owner,omitempty"`
+	Completions    string `json:"completions"`
+	Active         int32  `json:"active"`
+	Succeeded      int32  `json:"succeeded"`
+	Failed         int32  `json:"failed"`
+	Status         string `json:"status"`
+	StartTime      string `json:"startTime,omitempty"`
+	CompletionTime string `json:"completionTime,omitempty"`
+	Age            string `json:"age"`
+}
+
+type CronJobInfo struct {
+	Name               string   `json:"name"`
+	Schedule           string   `json:"schedule"`
+	TimeZone           string   `json:"timeZone,omitempty"`
+	Suspended          bool     `json:"suspended"`
+	Active             []string `json:"active,omitempty"`
+	LastScheduleTime   string   `json:"lastScheduleTime,omitempty"`
+	LastSuccessfulTime string   `json:"lastSuccessfulTime,omitempty"`
+	Age                string   `json:"age"`
+}
+
+type TriggerOutput struct {
+	Message string `json:"message"`
+	Job     string `json:"job"`
+	CronJob string `json:"cronJob"`
+}
+
+func GetJobs(namespace string) ([]JobInfo, error) {
+	_, err := clientset.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("namespace '%s' not found: %v", namespace, err)
+	}
+
+	list, err := clientset.BatchV1().Jobs(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %v", err)
+	}
+
+	var jobs []JobInfo
+	for _, job := range list.Items {
+		var completions int32 = 1
+		if job.Spec.Completions != nil {
+			completions = *job.Spec.Completions
+		}
+
+		info := JobInfo{
+			Name:        job.Name,
+			Completions: fmt.Sprintf("%d/%d", job.Status.Succeeded, completions),
+			Active:      job.Status.Active,
+			Succeeded:   job.Status.Succeeded,
+			Failed:      job.Status.Failed,
+			Status:      jobStatus(&job),
+			Age:         age(job.CreationTimestamp),
+		}
+		if ref := metav1.GetControllerOf(&job); ref != nil {
+			info.Owner = fmt.Sprintf("%s/%s", ref.Kind, ref.Name)
+		}
+		if job.Status.StartTime != nil {
+			info.StartTime = job.Status.StartTime.Format(time.RFC3339)
+		}
+		if job.Status.CompletionTime != nil {
+			info.CompletionTime = job.Status.CompletionTime.Format(time.RFC3339)
+		}
+		jobs = append(jobs, info)
+	}
+	return jobs, nil
+}
+
+func jobStatus(job *batchv1.Job) string {
+	for _, c := range job.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchv1.JobComplete:
+			return "Complete"
+		case batchv1.JobFailed:
+			return fmt.Sprintf("Failed (%s)", c.Reason)
+		case batchv1.JobSuspended:
+			return "Suspended"
+		}
+	}
+	if job.Status.Active > 0 {
+		return "Running"
+	}
+	return "Pending"
+}
+
+func GetCronJobs(namespace string) ([]CronJobInfo, error) {
+	_, err := clientset.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("namespace '%s' not found: %v", namespace, err)
+	}
+
+	list, err := clientset.BatchV1().CronJobs(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cronjobs: %v", err)
+	}
+
+	var cronJobs []CronJobInfo
+	for _, cj := range list.Items {
+		info := CronJobInfo{
+			Name:      cj.Name,
+			Schedule:  cj.Spec.Schedule,
+			Suspended: cj.Spec.Suspend != nil && *cj.Spec.Suspend,
+			Age:       age(cj.CreationTimestamp),
+		}
+		if cj.Spec.TimeZone != nil {
+			info.TimeZone = *cj.Spec.TimeZone
+		}
+		for _, ref := range cj.Status.Active {
+			info.Active = append(info.Active, ref.Name)
+		}
+		if cj.Status.LastScheduleTime != nil {
+			info.LastScheduleTime = cj.Status.LastScheduleTime.Format(time.RFC3339)
+		}
+		if cj.Status.LastSuccessfulTime != nil {
+			info.LastSuccessfulTime = cj.Status.LastSuccessfulTime.Format(time.RFC3339)
+		}
+		cronJobs = append(cronJobs, info)
+	}
+	return cronJobs, nil
+}
+
+// TriggerCronJob creates a Job from the jobTemplate of a CronJob, like `kubectl create job --from=cronjob/<name>`
+func TriggerCronJob(namespace, cronJob string) (*TriggerOutput, error) {
+	cj, err := clientset.BatchV1().CronJobs(namespace).Get(context.TODO(), cronJob, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cronjob: %v", err)
+	}
+
+	// job names are limited to 63 characters, the cronjob name is shortened if needed
+	suffix := fmt.Sprintf("-manual-%d", time.Now().Unix())
+	base := cj.Name
+	if len(base)+len(suffix) > 63 {
+		base = base[:63-len(suffix)]
+	}
+	name := base + suffix
+
+	annotations := map[string]string{"cronjob.kubernetes.io/instantiate": "manual"}
+	for k, v := range cj.Spec.JobTemplate.Annotations {
+		annotations[k] = v
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Labels:      cj.Spec.JobTemplate.Labels,
+			Annotations: annotations,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(cj, batchv1.SchemeGroupVersion.WithKind("CronJob")),
+			},
+		},
+		Spec: cj.Spec.JobTemplate.Spec,
+	}
+
+	created, err := clientset.BatchV1().Jobs(namespace).Create(context.TODO(), job, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job: %v", err)
+	}
+
+	return &TriggerOutput{Message: "Job created", Job: created.Name, CronJob: cj.Name}, nil
+}
@@ -4,24 +4,35 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
+	"sort"
 	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
 	ctlr "sigs.k8s.io/controller-runtime"
 
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
 	metricsclient "k8s.io/metrics/pkg/client/clientset/versioned"
 )
 
 var (
-	clientset     *kubernetes.Clientset
-	dynamicClient *dynamic.DynamicClient
+	clientset     kubernetes.Interface
+	dynamicClient dynamic.Interface
 	config        *rest.Config
+	restMapper    *restmapper.DeferredDiscoveryRESTMapper
+	configErr     error
 	ingressGVR    = schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}
 )
 
@@ -63,10 +74,32 @@ func GetDeployments(namespace string) ([]string, error) {
 	return names, nil
 }
 
-func GetIngresses(namespace string) ([]string, error) {
-	_, err := clientset.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
-	if err != nil {
-		return nil, err
+type IngressInfo struct {
+	Name         string        `json:"name"`
+	Namespace    string        `json:"namespace"`
+	IngressClass string        `json:"ingressClass,omitempty"`
+	Rules        []IngressRule `json:"rules"`
+	TLS          []IngressTLS  `json:"tls,omitempty"`
+}
+
+type IngressRule struct {
+	Host    string `json:"host"`
+	Path    string `json:"path"`
+	Backend string `json:"backend"`
+}
+
+type IngressTLS struct {
+	Hosts      []string `json:"hosts"`
+	SecretName string   `json:"secretName"`
+}
+
+// GetIngresses lists the ingresses of a namespace, or of all namespaces when namespace is empty
+func GetIngresses(namespace string) ([]IngressInfo, error) {
+	if namespace != "" {
+		_, err := clientset.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	list, err := dynamicClient.Resource(ingressGVR).Namespace(namespace).List(context.TODO(), metav1.ListOptions{})
@@ -74,13 +107,62 @@ func GetIngresses(namespace string) ([]string, error) {
 		return nil, fmt.Errorf("failed to list ingresses: %v", err)
 	}
 
-	var ingresses []string
-	for _, ing := range list.Items {
-		ingresses = append(ingresses, ing.GetName())
+	var ingresses []IngressInfo
+	for _, item := range list.Items {
+		var ing networkingv1.Ingress
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &ing); err != nil {
+			return nil, fmt.Errorf("failed to decode ingress '%s': %v", item.GetName(), err)
+		}
+		ingresses = append(ingresses, ingressInfo(&ing))
 	}
 	return ingresses, nil
 }
 
+func ingressInfo(ing *networkingv1.Ingress) IngressInfo {
+	info := IngressInfo{Name: ing.Name, Namespace: ing.Namespace}
+
+	if ing.Spec.IngressClassName != nil {
+		info.IngressClass = *ing.Spec.IngressClassName
+	} else if class, ok := ing.Annotations["kubernetes.io/ingress.class"]; ok {
+		info.IngressClass = class
+	}
+
+	if ing.Spec.DefaultBackend != nil {
+		info.Rules = append(info.Rules, IngressRule{Host: "*", Path: "/*", Backend: backendName(ing.Spec.DefaultBackend)})
+	}
+
+	for _, rule := range ing.Spec.Rules {
+		host := rule.Host
+		if host == "" {
+			host = "*"
+		}
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			info.Rules = append(info.Rules, IngressRule{Host: host, Path: path.Path, Backend: backendName(&path.Backend)})
+		}
+	}
+
+	for _, tls := range ing.Spec.TLS {
+		info.TLS = append(info.TLS, IngressTLS{Hosts: tls.Hosts, SecretName: tls.SecretName})
+	}
+	return info
+}
+
+func backendName(backend *networkingv1.IngressBackend) string {
+	if backend.Service != nil {
+		if backend.Service.Port.Name != "" {
+			return fmt.Sprintf("%s:%s", backend.Service.Name, backend.Service.Port.Name)
+		}
+		return fmt.Sprintf("%s:%d", backend.Service.Name, backend.Service.Port.Number)
+	}
+	if backend.Resource != nil {
+		return fmt.Sprintf("%s/%s", backend.Resource.Kind, backend.Resource.Name)
+	}
+	return ""
+}
+
 func GetNamespaceLabels(namespace string) (map[string]string, error) {
 	ns, err := clientset.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
 	if err != nil {
@@ -102,6 +184,74 @@ func GetNamespaces() ([]string, error) {
 	return namespaces, nil
 }
 
+type PodStatus struct {
+	Name                  string `json:"name"`
+	Phase                 string `json:"phase"`
+	Ready                 string `json:"ready"`
+	Restarts              int32  `json:"restarts"`
+	Node                  string `json:"node"`
+	Age                   string `json:"age"`
+	LastTerminationReason string `json:"lastTerminationReason,omitempty"`
+}
+
+// GetPods lists the pods of a namespace, optionally filtered by label and field selectors
+func GetPods(namespace, labelSelector, fieldSelector string) ([]PodStatus, error) {
+	_, err := clientset.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("namespace '%s' not found: %v", namespace, err)
+	}
+
+	list, err := clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: labelSelector,
+		FieldSelector: fieldSelector,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %v", err)
+	}
+
+	var pods []PodStatus
+	for _, pod := range list.Items {
+		var ready int
+		var restarts int32
+		var lastReason string
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.Ready {
+				ready++
+			}
+			restarts += cs.RestartCount
+			if cs.LastTerminationState.Terminated != nil {
+				lastReason = cs.LastTerminationState.Terminated.Reason
+			}
+		}
+
+		pods = append(pods, PodStatus{
+			Name:                  pod.Name,
+			Phase:                 string(pod.Status.Phase),
+			Ready:                 fmt.Sprintf("%d/%d", ready, len(pod.Spec.Containers)),
+			Restarts:              restarts,
+			Node:                  pod.Spec.NodeName,
+			Age:                   age(pod.CreationTimestamp),
+			LastTerminationReason: lastReason,
+		})
+	}
+	return pods, nil
+}
+
+// age renders the time elapsed since t the way kubectl does (e.g. 5m, 3h, 12d)
+func age(t metav1.Time) string {
+	d := time.Since(t.Time)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
 type RestartOutput struct {
 	Message string `json:"message"`
 	Pods    []Pod  `json:"pods"`
@@ -146,9 +296,35 @@ func RestartApplication(namespace, app string) (string, error) {
 }
 
 type PodCpuMemory struct {
-	Name   string `json:"name"`
-	CPU    string `json:"cpu"`
-	Memory string `json:"memory"`
+	Name      string `json:"name"`
+	CPU       string `json:"cpu"`
+	Memory    string `json:"memory"`
+	CPUMilli  int64  `json:"cpuMilli"`
+	MemoryMiB int64  `json:"memoryMiB"`
+
+	CPURequest         string  `json:"cpuRequest,omitempty"`
+	CPULimit           string  `json:"cpuLimit,omitempty"`
+	CPULimitPercent    float64 `json:"cpuLimitPercent,omitempty"`
+	MemoryRequest      string  `json:"memoryRequest,omitempty"`
+	MemoryLimit        string  `json:"memoryLimit,omitempty"`
+	MemoryLimitPercent float64 `json:"memoryLimitPercent,omitempty"`
+
+	Containers []ContainerCpuMemory `json:"containers"`
+}
+
+type ContainerCpuMemory struct {
+	Name      string `json:"name"`
+	CPU       string `json:"cpu"`
+	Memory    string `json:"memory"`
+	CPUMilli  int64  `json:"cpuMilli"`
+	MemoryMiB int64  `json:"memoryMiB"`
+
+	CPURequest         string  `json:"cpuRequest,omitempty"`
+	CPULimit           string  `json:"cpuLimit,omitempty"`
+	CPULimitPercent    float64 `json:"cpuLimitPercent,omitempty"`
+	MemoryRequest      string  `json:"memoryRequest,omitempty"`
+	MemoryLimit        string  `json:"memoryLimit,omitempty"`
+	MemoryLimitPercent float64 `json:"memoryLimitPercent,omitempty"`
 }
 
 func GetPodCpuMemory(namespace string) ([]PodCpuMemory, error) {
@@ -162,25 +338,137 @@ func GetPodCpuMemory(namespace string) ([]PodCpuMemory, error) {
 		return nil, fmt.Errorf("failed to get pod metrics: %v", err)
 	}
 
+	// pod specs provide the requests & limits the usage is compared against
+	podList, err := clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %v", err)
+	}
+
+	resources := map[string]corev1.ResourceRequirements{}
+	for _, pod := range podList.Items {
+		for _, c := range pod.Spec.Containers {
+			resources[pod.Name+"/"+c.Name] = c.Resources
+		}
+	}
+
 	var results []PodCpuMemory
 	for _, item := range podMetricsList.Items {
-		var totalCPU, totalMem string
-		for _, container := range item.Containers {
-			totalCPU = container.Usage.Cpu().String()
-			totalMem = container.Usage.Memory().String()
-		}
-		results = append(results, PodCpuMemory{
-			Name:   item.Name,
-			CPU:    totalCPU,
-			Memory: totalMem,
-		})
+		results = append(results, podCpuMemory(item, resources))
 	}
 
 	return results, nil
 }
 
+// podCpuMemory sums the usage of the containers of a pod, resources holds the requests and
+// limits of each container keyed by <pod>/<container>
+func podCpuMemory(item metricsv1beta1.PodMetrics, resources map[string]corev1.ResourceRequirements) PodCpuMemory {
+	var totalCPU, totalMem resource.Quantity
+	var cpuRequest, cpuLimit, memRequest, memLimit resource.Quantity
+	cpuLimited, memLimited := true, true
+
+	var containers []ContainerCpuMemory
+	for _, container := range item.Containers {
+		cpu := container.Usage.Cpu()
+		mem := container.Usage.Memory()
+		totalCPU.Add(*cpu)
+		totalMem.Add(*mem)
+
+		c := ContainerCpuMemory{
+			Name:      container.Name,
+			CPU:       cpu.String(),
+			Memory:    mem.String(),
+			CPUMilli:  cpu.MilliValue(),
+			MemoryMiB: mem.Value() / (1024 * 1024),
+		}
+
+		res := resources[item.Name+"/"+container.Name]
+		if q, ok := res.Requests[corev1.ResourceCPU]; ok {
+			c.CPURequest = q.String()
+			cpuRequest.Add(q)
+		}
+		if q, ok := res.Requests[corev1.ResourceMemory]; ok {
+			c.MemoryRequest = q.String()
+			memRequest.Add(q)
+		}
+		if q, ok := res.Limits[corev1.ResourceCPU]; ok {
+			c.CPULimit = q.String()
+			c.CPULimitPercent = percentOf(cpu.MilliValue(), q.MilliValue())
+			cpuLimit.Add(q)
+		} else {
+			cpuLimited = false
+		}
+		if q, ok := res.Limits[corev1.ResourceMemory]; ok {
+			c.MemoryLimit = q.String()
+			c.MemoryLimitPercent = percentOf(mem.Value(), q.Value())
+			memLimit.Add(q)
+		} else {
+			memLimited = false
+		}
+
+		containers = append(containers, c)
+	}
+
+	p := PodCpuMemory{
+		Name:       item.Name,
+		CPU:        totalCPU.String(),
+		Memory:     totalMem.String(),
+		CPUMilli:   totalCPU.MilliValue(),
+		MemoryMiB:  totalMem.Value() / (1024 * 1024),
+		Containers: containers,
+	}
+
+	if !cpuRequest.IsZero() {
+		p.CPURequest = cpuRequest.String()
+	}
+	if !memRequest.IsZero() {
+		p.MemoryRequest = memRequest.String()
+	}
+	// a pod level limit only exists when every container is limited
+	if cpuLimited && !cpuLimit.IsZero() {
+		p.CPULimit = cpuLimit.String()
+		p.CPULimitPercent = percentOf(totalCPU.MilliValue(), cpuLimit.MilliValue())
+	}
+	if memLimited && !memLimit.IsZero() {
+		p.MemoryLimit = memLimit.String()
+		p.MemoryLimitPercent = percentOf(totalMem.Value(), memLimit.Value())
+	}
+
+	return p
+}
+
+// SortPodCpuMemory orders pods by descending usage of "cpu" or "memory"
+func SortPodCpuMemory(metrics []PodCpuMemory, by string) error {
+	switch strings.ToLower(by) {
+	case "cpu":
+		sort.SliceStable(metrics, func(i, j int) bool { return metrics[i].CPUMilli > metrics[j].CPUMilli })
+	case "memory":
+		sort.SliceStable(metrics, func(i, j int) bool { return metrics[i].MemoryMiB > metrics[j].MemoryMiB })
+	default:
+		return fmt.Errorf("invalid sort key '%s', valid keys are cpu and memory", by)
+	}
+	return nil
+}
+
+func percentOf(used, limit int64) float64 {
+	if limit == 0 {
+		return 0
+	}
+	return math.Round(float64(used)*1000/float64(limit)) / 10
+}
+
+// CheckConfig returns the error of loading the kubeconfig or in-cluster configuration,
+// the clients are only usable when it is nil
+func CheckConfig() error {
+	return configErr
+}
+
 func init() {
-	config = ctlr.GetConfigOrDie()
+	// don't exit here, packages importing kube are unit tested without a cluster
+	config, configErr = ctlr.GetConfig()
+	if configErr != nil {
+		return
+	}
 	clientset = kubernetes.NewForConfigOrDie(config)
 	dynamicClient = dynamic.NewForConfigOrDie(config)
+	restMapper = restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(clientset.Discovery()))
 }
@@ -0,0 +1,146 @@
+package kube
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+// fakeClientset replaces the clientset of the package for the duration of a test
+func fakeClientset(t *testing.T, objects ...runtime.Object) *fake.Clientset {
+	t.Helper()
+
+	client := fake.NewSimpleClientset(objects...)
+	previous := clientset
+	clientset = client
+	t.Cleanup(func() { clientset = previous })
+	return client
+}
+
+// fakeDynamicClient replaces the dynamic client of the package for the duration of a test
+func fakeDynamicClient(t *testing.T, objects ...runtime.Object) {
+	t.Helper()
+
+	previous := dynamicClient
+	dynamicClient = dynamicfake.NewSimpleDynamicClient(scheme.Scheme, objects...)
+	t.Cleanup(func() { dynamicClient = previous })
+}
+
+func usage(name, cpu, memory string) metricsv1beta1.ContainerMetrics {
+	return metricsv1beta1.ContainerMetrics{
+		Name: name,
+		Usage: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+		},
+	}
+}
+
+func requirements(cpuRequest, cpuLimit, memLimit string) corev1.ResourceRequirements {
+	res := corev1.ResourceRequirements{Requests: corev1.ResourceList{}, Limits: corev1.ResourceList{}}
+	if cpuRequest != "" {
+		res.Requests[corev1.ResourceCPU] = resource.MustParse(cpuRequest)
+	}
+	if cpuLimit != "" {
+		res.Limits[corev1.ResourceCPU] = resource.MustParse(cpuLimit)
+	}
+	if memLimit != "" {
+		res.Limits[corev1.ResourceMemory] = resource.MustParse(memLimit)
+	}
+	return res
+}
+
+func TestPodCpuMemory(t *testing.T) {
+	tests := []struct {
+		name       string
+		containers []metricsv1beta1.ContainerMetrics
+		resources  map[string]corev1.ResourceRequirements
+
+		cpuMilli           int64
+		memoryMiB          int64
+		cpuRequest         string
+		cpuLimit           string
+		cpuLimitPercent    float64
+		memoryLimit        string
+		memoryLimitPercent float64
+	}{
+		{
+			name:       "single container without resources",
+			containers: []metricsv1beta1.ContainerMetrics{usage("app", "250m", "64Mi")},
+			cpuMilli:   250,
+			memoryMiB:  64,
+		},
+		{
+			name: "mixed units are summed",
+			containers: []metricsv1beta1.ContainerMetrics{
+				usage("app", "1500m", "1Gi"),
+				usage("sidecar", "500u", "512Mi"),
+			},
+			cpuMilli:  1501,
+			memoryMiB: 1536,
+		},
+		{
+			name: "requests and limits of every container",
+			containers: []metricsv1beta1.ContainerMetrics{
+				usage("app", "300m", "100Mi"),
+				usage("sidecar", "100m", "28Mi"),
+			},
+			resources: map[string]corev1.ResourceRequirements{
+				"pod/app":     requirements("200m", "1", "256Mi"),
+				"pod/sidecar": requirements("50m", "500m", "256Mi"),
+			},
+			cpuMilli:           400,
+			memoryMiB:          128,
+			cpuRequest:         "250m",
+			cpuLimit:           "1500m",
+			cpuLimitPercent:    26.7,
+			memoryLimit:        "512Mi",
+			memoryLimitPercent: 25,
+		},
+		{
+			name: "no pod limit when a container is unlimited",
+			containers: []metricsv1beta1.ContainerMetrics{
+				usage("app", "300m", "100Mi"),
+				usage("sidecar", "100m", "28Mi"),
+			},
+			resources: map[string]corev1.ResourceRequirements{
+				"pod/app":     requirements("", "1", "256Mi"),
+				"pod/sidecar": requirements("", "", "256Mi"),
+			},
+			cpuMilli:           400,
+			memoryMiB:          128,
+			memoryLimit:        "512Mi",
+			memoryLimitPercent: 25,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := metricsv1beta1.PodMetrics{ObjectMeta: metav1.ObjectMeta{Name: "pod"}, Containers: tt.containers}
+			got := podCpuMemory(item, tt.resources)
+
+			if got.Name != "pod" || len(got.Containers) != len(tt.containers) {
+				t.Fatalf("got pod %s with %d containers, want pod with %d", got.Name, len(got.Containers), len(tt.containers))
+			}
+			if got.CPUMilli != tt.cpuMilli || got.MemoryMiB != tt.memoryMiB {
+				t.Errorf("usage = %dm %dMiB, want %dm %dMiB", got.CPUMilli, got.MemoryMiB, tt.cpuMilli, tt.memoryMiB)
+			}
+			if got.CPURequest != tt.cpuRequest {
+				t.Errorf("cpuRequest = %q, want %q", got.CPURequest, tt.cpuRequest)
+			}
+			if got.CPULimit != tt.cpuLimit || got.CPULimitPercent != tt.cpuLimitPercent {
+				t.Errorf("cpuLimit = %q %v%%, want %q %v%%", got.CPULimit, got.CPULimitPercent, tt.cpuLimit, tt.cpuLimitPercent)
+			}
+			if got.MemoryLimit != tt.memoryLimit || got.MemoryLimitPercent != tt.memoryLimitPercent {
+				t.Errorf("memoryLimit = %q %v%%, want %q %v%%", got.MemoryLimit, got.MemoryLimitPercent, tt.memoryLimit, tt.memoryLimitPercent)
+			}
+		})
+	}
+}
@@ -0,0 +1,457 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	metricsclient "k8s.io/metrics/pkg/client/clientset/versioned"
+)
+
+type NodeInfo struct {
+	Name           string      `json:"name"`
+	Roles          []string    `json:"roles,omitempty"`
+	Ready          bool        `json:"ready"`
+	Unschedulable  bool        `json:"unschedulable"`
+	KubeletVersion string      `json:"kubeletVersion"`
+	Age            string      `json:"age"`
+	Conditions     []Condition `json:"conditions"`
+	Taints         []string    `json:"taints,omitempty"`
+
+	AllocatableCPU         string   `json:"allocatableCpu"`
+	AllocatableMemory      string   `json:"allocatableMemory"`
+	AllocatablePods        string   `json:"allocatablePods"`
+	RequestedCPU           string   `json:"requestedCpu"`
+	RequestedMemory        string   `json:"requestedMemory"`
+	RequestedCPUPercent    float64  `json:"requestedCpuPercent"`
+	RequestedMemoryPercent float64  `json:"requestedMemoryPercent"`
+	RunningPods            int      `json:"runningPods"`
+	UnderPressure          []string `json:"underPressure,omitempty"`
+}
+
+type NodeCpuMemory struct {
+	Name          string  `json:"name"`
+	CPU           string  `json:"cpu"`
+	Memory        string  `json:"memory"`
+	CPUMilli      int64   `json:"cpuMilli"`
+	MemoryMiB     int64   `json:"memoryMiB"`
+	CPUPercent    float64 `json:"cpuPercent"`
+	MemoryPercent float64 `json:"memoryPercent"`
+}
+
+// GetNodes returns the inventory of cluster nodes with allocatable vs. requested resources
+func GetNodes() ([]NodeInfo, error) {
+	list, err := clientset.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %v", err)
+	}
+
+	// requests of all non-terminated pods, grouped by node
+	pods, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{
+		FieldSelector: "status.phase!=Succeeded,status.phase!=Failed",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %v", err)
+	}
+
+	requestedCPU := map[string]*resource.Quantity{}
+	requestedMem := map[string]*resource.Quantity{}
+	podCount := map[string]int{}
+	for _, pod := range pods.Items {
+		node := pod.Spec.NodeName
+		if node == "" {
+			continue
+		}
+		if _, ok := requestedCPU[node]; !ok {
+			requestedCPU[node] = resource.NewQuantity(0, resource.DecimalSI)
+			requestedMem[node] = resource.NewQuantity(0, resource.BinarySI)
+		}
+		for _, c := range pod.Spec.Containers {
+			if q, ok := c.Resources.Requests[corev1.ResourceCPU]; ok {
+				requestedCPU[node].Add(q)
+			}
+			if q, ok := c.Resources.Requests[corev1.ResourceMemory]; ok {
+				requestedMem[node].Add(q)
+			}
+		}
+		podCount[node]++
+	}
+
+	var nodes []NodeInfo
+	for _, node := range list.Items {
+		info := NodeInfo{
+			Name:              node.Name,
+			Roles:             nodeRoles(&node),
+			Unschedulable:     node.Spec.Unschedulable,
+			KubeletVersion:    node.Status.NodeInfo.KubeletVersion,
+			Age:               age(node.CreationTimestamp),
+			AllocatableCPU:    node.Status.Allocatable.Cpu().String(),
+			AllocatableMemory: node.Status.Allocatable.Memory().String(),
+			AllocatablePods:   node.Status.Allocatable.Pods().String(),
+			RequestedCPU:      "0",
+			RequestedMemory:   "0",
+			RunningPods:       podCount[node.Name],
+		}
+
+		for _, c := range node.Status.Conditions {
+			info.Conditions = append(info.Conditions, Condition{
+				Type:    string(c.Type),
+				Status:  string(c.Status),
+				Reason:  c.Reason,
+				Message: c.Message,
+			})
+			if c.Type == corev1.NodeReady {
+				info.Ready = c.Status == corev1.ConditionTrue
+			} else if c.Status == corev1.ConditionTrue {
+				// MemoryPressure, DiskPressure, PIDPressure, NetworkUnavailable
+				info.UnderPressure = append(info.UnderPressure, string(c.Type))
+			}
+		}
+
+		for _, t := range node.Spec.Taints {
+			info.Taints = append(info.Taints, fmt.Sprintf("%s=%s:%s", t.Key, t.Value, t.Effect))
+		}
+
+		if q, ok := requestedCPU[node.Name]; ok {
+			info.RequestedCPU = q.String()
+			info.RequestedCPUPercent = percentOf(q.MilliValue(), node.Status.Allocatable.Cpu().MilliValue())
+		}
+		if q, ok := requestedMem[node.Name]; ok {
+			info.RequestedMemory = q.String()
+			info.RequestedMemoryPercent = percentOf(q.Value(), node.Status.Allocatable.Memory().Value())
+		}
+
+		nodes = append(nodes, info)
+	}
+	return nodes, nil
+}
+
+// GetNodeCpuMemory returns live node usage from metrics.k8s.io, busiest nodes first
+func GetNodeCpuMemory() ([]NodeCpuMemory, error) {
+	metricsClient, err := metricsclient.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metrics client: %v", err)
+	}
+
+	nodeMetricsList, err := metricsClient.MetricsV1beta1().NodeMetricses().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node metrics: %v", err)
+	}
+
+	list, err := clientset.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %v", err)
+	}
+
+	allocatable := map[string]corev1.ResourceList{}
+	for _, node := range list.Items {
+		allocatable[node.Name] = node.Status.Allocatable
+	}
+
+	var results []NodeCpuMemory
+	for _, item := range nodeMetricsList.Items {
+		cpu := item.Usage.Cpu()
+		mem := item.Usage.Memory()
+
+		n := NodeCpuMemory{
+			Name:      item.Name,
+			CPU:       cpu.String(),
+			Memory:    mem.String(),
+			CPUMilli:  cpu.MilliValue(),
+			MemoryMiB: mem.Value() / (1024 * 1024),
+		}
+		if alloc, ok := allocatable[item.Name]; ok {
+			n.CPUPercent = percentOf(cpu.MilliValue(), alloc.Cpu().MilliValue())
+			n.MemoryPercent = percentOf(mem.Value(), alloc.Memory().Value())
+		}
+		results = append(results, n)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return max(results[i].CPUPercent, results[i].MemoryPercent) > max(results[j].CPUPercent, results[j].MemoryPercent)
+	})
+
+	return results, nil
+}
+
+func nodeRoles(node *corev1.Node) []string {
+	var roles []string
+	for label := range node.Labels {
+		if role, found := strings.CutPrefix(label, "node-role.kubernetes.io/"); found && role != "" {
+			roles = append(roles, role)
+		}
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+const (
+	DefaultDrainTimeout = 5 * time.Minute
+
+	// evictions sent to the API server at the same time by a drain
+	maxConcurrentEvictions = 10
+)
+
+var (
+	// delay between eviction attempts blocked by a PodDisruptionBudget
+	evictionRetryInterval = 5 * time.Second
+
+	// delay between the checks for evicted pods still on the node
+	terminationPollInterval = time.Second
+)
+
+// ProgressFunc receives progress updates of long running operations
+type ProgressFunc func(progress, total float64, message string)
+
+type CordonOutput struct {
+	Message       string `json:"message"`
+	Node          string `json:"node"`
+	Unschedulable bool   `json:"unschedulable"`
+}
+
+type DrainReport struct {
+	Node     string   `json:"node"`
+	DryRun   bool     `json:"dryRun"`
+	Evicted  []string `json:"evicted,omitempty"`
+	Blocked  []string `json:"blockedByDisruptionBudget,omitempty"`
+	Skipped  []string `json:"skipped,omitempty"`
+	Failed   []string `json:"failed,omitempty"`
+	Pending  []string `json:"pending,omitempty"`
+	Complete bool     `json:"complete"`
+}
+
+// CordonNode marks a node unschedulable, or schedulable again when cordon is false
+func CordonNode(name string, cordon bool) (*CordonOutput, error) {
+	patch := fmt.Sprintf(`{"spec":{"unschedulable":%t}}`, cordon)
+	node, err := clientset.CoreV1().Nodes().Patch(context.TODO(), name, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch node: %v", err)
+	}
+
+	message := "Node cordoned"
+	if !cordon {
+		message = "Node uncordoned"
+	}
+	return &CordonOutput{Message: message, Node: node.Name, Unschedulable: node.Spec.Unschedulable}, nil
+}
+
+// DrainNode cordons a node and evicts its pods through the Eviction API, so that
+// PodDisruptionBudgets are respected. DaemonSet pods, mirror pods, pods without a
+// controller and pods in namespaces refused by check are skipped. With dryRun the
+// evictions are only validated by the API server.
+func DrainNode(name string, dryRun bool, timeout time.Duration, check NamespaceCheck, progress ProgressFunc) (*DrainReport, error) {
+	if timeout <= 0 {
+		timeout = DefaultDrainTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.TODO(), timeout)
+	defer cancel()
+
+	if !dryRun {
+		if _, err := CordonNode(name, true); err != nil {
+			return nil, err
+		}
+	}
+
+	list, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: "spec.nodeName=" + name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %v", err)
+	}
+
+	report := &DrainReport{Node: name, DryRun: dryRun}
+
+	var pods []corev1.Pod
+	denied := 0
+	for _, pod := range list.Items {
+		if reason := skipEviction(&pod); reason != "" {
+			report.Skipped = append(report.Skipped, fmt.Sprintf("%s/%s (%s)", pod.Namespace, pod.Name, reason))
+			continue
+		}
+		if check != nil {
+			if reason := check(pod.Namespace); reason != "" {
+				report.Skipped = append(report.Skipped, fmt.Sprintf("%s/%s (denied by the server policy: %s)", pod.Namespace, pod.Name, reason))
+				denied++
+				continue
+			}
+		}
+		pods = append(pods, pod)
+	}
+
+	// every pod counts twice unless in dry-run: once evicted and once terminated
+	total := float64(len(pods))
+	if !dryRun {
+		total *= 2
+	}
+	progress(0, total, fmt.Sprintf("Evicting %d pods from node %s", len(pods), name))
+
+	var deleteOptions *metav1.DeleteOptions
+	if dryRun {
+		deleteOptions = &metav1.DeleteOptions{DryRun: []string{metav1.DryRunAll}}
+	}
+
+	// evictions are sent concurrently, so that a pod blocked by a PodDisruptionBudget
+	// doesn't hold back the others until the timeout
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		evicted   []corev1.Pod
+		processed float64
+	)
+	slots := make(chan struct{}, maxConcurrentEvictions)
+	for _, pod := range pods {
+		wg.Add(1)
+		go func(pod corev1.Pod) {
+			defer wg.Done()
+			podName := pod.Namespace + "/" + pod.Name
+
+			var err error
+			select {
+			case slots <- struct{}{}:
+				err = evictPod(ctx, &pod, deleteOptions, dryRun)
+				<-slots
+			case <-ctx.Done():
+				err = fmt.Errorf("eviction not attempted before timeout")
+			}
+
+			mu.Lock()
+			switch {
+			case err == nil:
+				evicted = append(evicted, pod)
+			case apierrors.IsTooManyRequests(err):
+				report.Blocked = append(report.Blocked, fmt.Sprintf("%s (%v)", podName, err))
+			case ctx.Err() != nil:
+				// the timeout expired before the API server answered
+				report.Pending = append(report.Pending, fmt.Sprintf("%s (%v)", podName, err))
+			default:
+				report.Failed = append(report.Failed, fmt.Sprintf("%s (%v)", podName, err))
+			}
+			processed++
+			done := processed
+			mu.Unlock()
+
+			// reported outside of the lock, a slow client must not hold back the evictions
+			progress(done, total, fmt.Sprintf("Processed %s", podName))
+		}(pod)
+	}
+	wg.Wait()
+
+	var running []corev1.Pod
+	if !dryRun && len(evicted) > 0 {
+		progress(processed, total, fmt.Sprintf("Waiting for %d evicted pods to terminate", len(evicted)))
+		running = waitForTermination(ctx, name, evicted, func(terminated int) {
+			progress(processed+float64(terminated), total, fmt.Sprintf("%d of %d evicted pods terminated", terminated, len(evicted)))
+		})
+	}
+
+	stillRunning := map[types.UID]bool{}
+	for _, pod := range running {
+		stillRunning[pod.UID] = true
+	}
+	for _, pod := range evicted {
+		podName := pod.Namespace + "/" + pod.Name
+		if stillRunning[pod.UID] {
+			report.Pending = append(report.Pending, fmt.Sprintf("%s (evicted but not terminated before timeout)", podName))
+		} else {
+			report.Evicted = append(report.Evicted, podName)
+		}
+	}
+
+	for _, list := range [][]string{report.Evicted, report.Blocked, report.Pending, report.Failed} {
+		sort.Strings(list)
+	}
+	// pods left behind by the policy still run on the node
+	report.Complete = denied == 0 && len(report.Blocked) == 0 && len(report.Pending) == 0 && len(report.Failed) == 0
+	return report, nil
+}
+
+// evictPod evicts a pod, retrying while a PodDisruptionBudget blocks the eviction
+func evictPod(ctx context.Context, pod *corev1.Pod, deleteOptions *metav1.DeleteOptions, dryRun bool) error {
+	eviction := &policyv1.Eviction{
+		ObjectMeta:    metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+		DeleteOptions: deleteOptions,
+	}
+
+	for {
+		err := clientset.PolicyV1().Evictions(pod.Namespace).Evict(ctx, eviction)
+		if err == nil || apierrors.IsNotFound(err) {
+			return nil
+		}
+		// a dry-run only reports whether the budget currently allows the eviction
+		if !apierrors.IsTooManyRequests(err) || dryRun {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(evictionRetryInterval):
+		}
+	}
+}
+
+// waitForTermination polls the pods of a node until the evicted pods are gone and
+// returns those still running when ctx expires. terminated is called whenever more
+// pods are gone.
+func waitForTermination(ctx context.Context, node string, evicted []corev1.Pod, terminated func(int)) []corev1.Pod {
+	remaining := evicted
+	for {
+		list, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+			FieldSelector: "spec.nodeName=" + node,
+		})
+		if err == nil {
+			present := map[types.UID]bool{}
+			for _, pod := range list.Items {
+				present[pod.UID] = true
+			}
+
+			var running []corev1.Pod
+			for _, pod := range remaining {
+				if present[pod.UID] {
+					running = append(running, pod)
+				}
+			}
+			if len(running) < len(remaining) {
+				terminated(len(evicted) - len(running))
+			}
+			if remaining = running; len(remaining) == 0 {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return remaining
+		case <-time.After(terminationPollInterval):
+		}
+	}
+}
+
+// skipEviction returns why a pod must not be evicted by a drain, or "" when it can be
+func skipEviction(pod *corev1.Pod) string {
+	if _, ok := pod.Annotations[corev1.MirrorPodAnnotationKey]; ok {
+		return "mirror pod"
+	}
+
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			return ""
+		}
+		return "not managed by a controller"
+	}
+	if ref.Kind == "DaemonSet" {
+		return "DaemonSet pod"
+	}
+	return ""
+}
@@ -0,0 +1,256 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var podsResource = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+
+// fastRetries shortens the eviction retry and termination poll intervals
+func fastRetries(t *testing.T) {
+	t.Helper()
+
+	retry, poll := evictionRetryInterval, terminationPollInterval
+	evictionRetryInterval, terminationPollInterval = 5*time.Millisecond, 5*time.Millisecond
+	t.Cleanup(func() { evictionRetryInterval, terminationPollInterval = retry, poll })
+}
+
+// onEviction answers the evictions of the fake clientset with the error returned by
+// respond for the pod and its attempt number, nil evicts the pod unless in dry-run
+func onEviction(client *fake.Clientset, respond func(name string, attempt int) error) {
+	var mu sync.Mutex
+	attempts := map[string]int{}
+
+	client.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		eviction := action.(k8stesting.CreateAction).GetObject().(*policyv1.Eviction)
+
+		mu.Lock()
+		attempts[eviction.Name]++
+		attempt := attempts[eviction.Name]
+		mu.Unlock()
+
+		if err := respond(eviction.Name, attempt); err != nil {
+			return true, nil, err
+		}
+		if eviction.DeleteOptions != nil && len(eviction.DeleteOptions.DryRun) > 0 {
+			return true, nil, nil
+		}
+		return true, nil, client.Tracker().Delete(podsResource, action.GetNamespace(), eviction.Name)
+	})
+}
+
+func disruptionBudget() error {
+	return apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0)
+}
+
+func testPod(namespace, name, controllerKind string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, UID: types.UID(namespace + "-" + name)},
+		Spec:       corev1.PodSpec{NodeName: "worker-1"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	if controllerKind != "" {
+		controller := true
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: controllerKind, Name: name + "-owner", Controller: &controller}}
+	}
+	return pod
+}
+
+func TestEvictPod(t *testing.T) {
+	fastRetries(t)
+
+	tests := []struct {
+		name     string
+		dryRun   bool
+		timeout  time.Duration
+		respond  func(attempt int) error
+		attempts int
+		wantErr  func(error) bool
+	}{
+		{
+			name:     "evicted",
+			respond:  func(int) error { return nil },
+			attempts: 1,
+		},
+		{
+			name: "retried while the budget blocks the eviction",
+			respond: func(attempt int) error {
+				if attempt < 3 {
+					return disruptionBudget()
+				}
+				return nil
+			},
+			attempts: 3,
+		},
+		{
+			name:     "dry run is not retried",
+			dryRun:   true,
+			respond:  func(int) error { return disruptionBudget() },
+			attempts: 1,
+			wantErr:  apierrors.IsTooManyRequests,
+		},
+		{
+			name:     "pod already gone",
+			respond:  func(int) error { return apierrors.NewNotFound(podsResource.GroupResource(), "cart-1") },
+			attempts: 1,
+		},
+		{
+			name: "other errors are not retried",
+			respond: func(int) error {
+				return apierrors.NewForbidden(podsResource.GroupResource(), "cart-1", fmt.Errorf("RBAC"))
+			},
+			attempts: 1,
+			wantErr:  apierrors.IsForbidden,
+		},
+		{
+			name:    "blocked until the timeout",
+			timeout: 50 * time.Millisecond,
+			respond: func(int) error { return disruptionBudget() },
+			wantErr: apierrors.IsTooManyRequests,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := testPod("shop", "cart-1", "ReplicaSet")
+			client := fakeClientset(t, pod)
+			attempts := 0
+			onEviction(client, func(name string, attempt int) error {
+				attempts = attempt
+				return tt.respond(attempt)
+			})
+
+			timeout := tt.timeout
+			if timeout == 0 {
+				timeout = 5 * time.Second
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			err := evictPod(ctx, pod, nil, tt.dryRun)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("evictPod() error = %v", err)
+			}
+			if tt.wantErr != nil && !tt.wantErr(err) {
+				t.Fatalf("evictPod() error = %v", err)
+			}
+			if tt.attempts > 0 && attempts != tt.attempts {
+				t.Errorf("%d eviction attempts, want %d", attempts, tt.attempts)
+			}
+			if tt.attempts == 0 && attempts < 2 {
+				t.Errorf("%d eviction attempts, want retries until the timeout", attempts)
+			}
+		})
+	}
+}
+
+func TestDrainNode(t *testing.T) {
+	fastRetries(t)
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}}
+	client := fakeClientset(t, node,
+		testPod("shop", "cart-1", "ReplicaSet"),
+		testPod("shop", "cart-2", "ReplicaSet"),
+		testPod("shop", "checkout-1", "StatefulSet"),
+		testPod("kube-system", "fluentd", "DaemonSet"),
+		testPod("shop", "debug", ""),
+		testPod("vault", "vault-0", "StatefulSet"),
+	)
+	onEviction(client, func(name string, attempt int) error {
+		switch name {
+		case "cart-2":
+			// the budget never allows it
+			return disruptionBudget()
+		case "checkout-1":
+			return apierrors.NewInternalError(fmt.Errorf("etcd timeout"))
+		}
+		return nil
+	})
+
+	check := func(namespace string) string {
+		if namespace == "vault" {
+			return "denied"
+		}
+		return ""
+	}
+
+	var mu sync.Mutex
+	var progress []float64
+	report, err := DrainNode("worker-1", false, 200*time.Millisecond, check, func(done, total float64, message string) {
+		mu.Lock()
+		defer mu.Unlock()
+		progress = append(progress, done)
+		if total != 6 {
+			t.Errorf("progress total = %v, want 6", total)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cordoned, err := client.CoreV1().Nodes().Get(context.Background(), "worker-1", metav1.GetOptions{})
+	if err != nil || !cordoned.Spec.Unschedulable {
+		t.Errorf("node is not cordoned: %v", err)
+	}
+
+	if !reflect.DeepEqual(report.Evicted, []string{"shop/cart-1"}) {
+		t.Errorf("evicted = %v, want [shop/cart-1]", report.Evicted)
+	}
+	if len(report.Blocked) != 1 || len(report.Failed) != 1 || len(report.Skipped) != 3 {
+		t.Errorf("blocked = %v, failed = %v, skipped = %v", report.Blocked, report.Failed, report.Skipped)
+	}
+	if report.Complete {
+		t.Errorf("drain reported complete with blocked, failed and denied pods")
+	}
+
+	// start, one update per processed pod, the wait and one per terminated pod
+	if len(progress) != 6 || progress[len(progress)-1] != 4 {
+		t.Errorf("progress = %v, want 6 updates ending at 4", progress)
+	}
+}
+
+func TestDrainNodeDryRun(t *testing.T) {
+	fastRetries(t)
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}}
+	client := fakeClientset(t, node, testPod("shop", "cart-1", "ReplicaSet"), testPod("shop", "cart-2", "ReplicaSet"))
+	onEviction(client, func(name string, attempt int) error {
+		if name == "cart-2" {
+			return disruptionBudget()
+		}
+		return nil
+	})
+
+	report, err := DrainNode("worker-1", true, time.Second, nil, func(float64, float64, string) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if node, _ := client.CoreV1().Nodes().Get(context.Background(), "worker-1", metav1.GetOptions{}); node.Spec.Unschedulable {
+		t.Errorf("dry run cordoned the node")
+	}
+	if !reflect.DeepEqual(report.Evicted, []string{"shop/cart-1"}) || len(report.Blocked) != 1 || report.Complete {
+		t.Errorf("evicted = %v, blocked = %v, complete = %v, want cart-1 evicted and cart-2 blocked by its budget", report.Evicted, report.Blocked, report.Complete)
+	}
+	if _, err := client.CoreV1().Pods("shop").Get(context.Background(), "cart-1", metav1.GetOptions{}); err != nil {
+		t.Errorf("dry run evicted cart-1: %v", err)
+	}
+}
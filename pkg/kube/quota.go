@@ -0,0 +1,144 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// utilization (in percent) above which a quota resource is flagged
+	DefaultQuotaThreshold = 80.0
+)
+
+type QuotaReport struct {
+	Namespace   string           `json:"namespace"`
+	Threshold   float64          `json:"threshold"`
+	Quotas      []QuotaInfo      `json:"quotas"`
+	LimitRanges []LimitRangeInfo `json:"limitRanges,omitempty"`
+	Flagged     []string         `json:"flagged,omitempty"`
+}
+
+type QuotaInfo struct {
+	Name      string       `json:"name"`
+	Resources []QuotaUsage `json:"resources"`
+}
+
+type QuotaUsage struct {
+	Resource string  `json:"resource"`
+	Hard     string  `json:"hard"`
+	Used     string  `json:"used"`
+	Percent  float64 `json:"percent"`
+	Flagged  bool    `json:"flagged,omitempty"`
+}
+
+type LimitRangeInfo struct {
+	Name   string            `json:"name"`
+	Limits []LimitRangeLimit `json:"limits"`
+}
+
+type LimitRangeLimit struct {
+	Type           string            `json:"type"`
+	Default        map[string]string `json:"default,omitempty"`
+	DefaultRequest map[string]string `json:"defaultRequest,omitempty"`
+	Min            map[string]string `json:"min,omitempty"`
+	Max            map[string]string `json:"max,omitempty"`
+}
+
+// GetQuotas reports ResourceQuota usage and LimitRange defaults of a namespace
+func GetQuotas(namespace string, threshold float64) (*QuotaReport, error) {
+	_, err := clientset.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("namespace '%s' not found: %v", namespace, err)
+	}
+
+	if threshold <= 0 {
+		threshold = DefaultQuotaThreshold
+	}
+
+	quotas, err := clientset.CoreV1().ResourceQuotas(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resource quotas: %v", err)
+	}
+
+	limitRanges, err := clientset.CoreV1().LimitRanges(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list limit ranges: %v", err)
+	}
+
+	report := &QuotaReport{Namespace: namespace, Threshold: threshold}
+
+	for _, q := range quotas.Items {
+		info, flagged := quotaUsage(&q, threshold)
+		report.Quotas = append(report.Quotas, info)
+		report.Flagged = append(report.Flagged, flagged...)
+	}
+
+	for _, lr := range limitRanges.Items {
+		info := LimitRangeInfo{Name: lr.Name}
+		for _, l := range lr.Spec.Limits {
+			info.Limits = append(info.Limits, LimitRangeLimit{
+				Type:           string(l.Type),
+				Default:        resourceListToMap(l.Default),
+				DefaultRequest: resourceListToMap(l.DefaultRequest),
+				Min:            resourceListToMap(l.Min),
+				Max:            resourceListToMap(l.Max),
+			})
+		}
+		report.LimitRanges = append(report.LimitRanges, info)
+	}
+
+	return report, nil
+}
+
+// quotaUsage returns the utilization of every resource of a quota and the resources at or
+// above threshold percent. A hard limit of 0 rejects every request and is always flagged.
+func quotaUsage(q *corev1.ResourceQuota, threshold float64) (QuotaInfo, []string) {
+	info := QuotaInfo{Name: q.Name}
+	var flagged []string
+
+	for _, name := range sortedResourceNames(q.Status.Hard) {
+		hard := q.Status.Hard[name]
+		used := q.Status.Used[name]
+
+		usage := QuotaUsage{
+			Resource: string(name),
+			Hard:     hard.String(),
+			Used:     used.String(),
+			Percent:  percentOf(used.MilliValue(), hard.MilliValue()),
+		}
+		if hard.IsZero() {
+			usage.Percent = 100
+			usage.Flagged = true
+			flagged = append(flagged, fmt.Sprintf("%s/%s has a hard limit of 0, every request for it is rejected", q.Name, name))
+		} else if usage.Percent >= threshold {
+			usage.Flagged = true
+			flagged = append(flagged, fmt.Sprintf("%s/%s at %.1f%% (%s of %s)", q.Name, name, usage.Percent, usage.Used, usage.Hard))
+		}
+		info.Resources = append(info.Resources, usage)
+	}
+	return info, flagged
+}
+
+func sortedResourceNames(list corev1.ResourceList) []corev1.ResourceName {
+	var names []corev1.ResourceName
+	for name := range list {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+func resourceListToMap(list corev1.ResourceList) map[string]string {
+	if len(list) == 0 {
+		return nil
+	}
+	result := make(map[string]string)
+	for name, q := range list {
+		result[string(name)] = q.String()
+	}
+	return result
+}
@@ -0,0 +1,78 @@
+package kube
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func resourceList(pairs ...string) corev1.ResourceList {
+	list := corev1.ResourceList{}
+	for i := 0; i < len(pairs); i += 2 {
+		list[corev1.ResourceName(pairs[i])] = resource.MustParse(pairs[i+1])
+	}
+	return list
+}
+
+func TestQuotaUsage(t *testing.T) {
+	tests := []struct {
+		name     string
+		hard     corev1.ResourceList
+		used     corev1.ResourceList
+		percents []float64
+		flagged  []string
+	}{
+		{
+			name:     "below the threshold",
+			hard:     resourceList("limits.memory", "4Gi", "pods", "10"),
+			used:     resourceList("limits.memory", "1Gi", "pods", "3"),
+			percents: []float64{25, 30},
+		},
+		{
+			name:     "at and above the threshold",
+			hard:     resourceList("requests.cpu", "2", "pods", "10"),
+			used:     resourceList("requests.cpu", "1900m", "pods", "8"),
+			percents: []float64{80, 95},
+			flagged:  []string{"compute/pods at 80.0% (8 of 10)", "compute/requests.cpu at 95.0% (1900m of 2)"},
+		},
+		{
+			name:     "hard limit of 0",
+			hard:     resourceList("services.loadbalancers", "0", "pods", "10"),
+			used:     resourceList("pods", "1"),
+			percents: []float64{10, 100},
+			flagged:  []string{"compute/services.loadbalancers has a hard limit of 0, every request for it is rejected"},
+		},
+		{
+			name:     "no usage reported yet",
+			hard:     resourceList("pods", "10"),
+			percents: []float64{0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &corev1.ResourceQuota{
+				ObjectMeta: metav1.ObjectMeta{Name: "compute"},
+				Status:     corev1.ResourceQuotaStatus{Hard: tt.hard, Used: tt.used},
+			}
+
+			info, flagged := quotaUsage(q, DefaultQuotaThreshold)
+			var percents []float64
+			for _, r := range info.Resources {
+				percents = append(percents, r.Percent)
+				if r.Flagged != (r.Percent >= DefaultQuotaThreshold) {
+					t.Errorf("%s at %.1f%% flagged = %v", r.Resource, r.Percent, r.Flagged)
+				}
+			}
+			if !reflect.DeepEqual(percents, tt.percents) {
+				t.Errorf("percents = %v, want %v", percents, tt.percents)
+			}
+			if !reflect.DeepEqual(flagged, tt.flagged) {
+				t.Errorf("flagged = %q, want %q", flagged, tt.flagged)
+			}
+		})
+	}
+}
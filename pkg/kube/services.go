@@ -0,0 +1,145 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+type ServiceInfo struct {
+	Name         string            `json:"name"`
+	Type         string            `json:"type"`
+	ClusterIP    string            `json:"clusterIP,omitempty"`
+	ExternalIPs  []string          `json:"externalIPs,omitempty"`
+	ExternalName string            `json:"externalName,omitempty"`
+	Ports        []string          `json:"ports,omitempty"`
+	Selector     map[string]string `json:"selector,omitempty"`
+	MatchedPods  int               `json:"matchedPods"`
+	Ready        []EndpointAddress `json:"readyEndpoints"`
+	NotReady     []EndpointAddress `json:"notReadyEndpoints,omitempty"`
+	Warnings     []string          `json:"warnings,omitempty"`
+}
+
+type EndpointAddress struct {
+	Address string `json:"address"`
+	Pod     string `json:"pod,omitempty"`
+	Node    string `json:"node,omitempty"`
+}
+
+// InspectServices returns the routing details and endpoint health of one Service,
+// or of every Service of the namespace when name is empty
+func InspectServices(namespace, name string) ([]ServiceInfo, error) {
+	var items []corev1.Service
+	if name != "" {
+		svc, err := clientset.CoreV1().Services(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get service '%s': %v", name, err)
+		}
+		items = append(items, *svc)
+	} else {
+		list, err := clientset.CoreV1().Services(namespace).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list services: %v", err)
+		}
+		items = list.Items
+	}
+
+	var services []ServiceInfo
+	for _, svc := range items {
+		info, err := inspectService(&svc)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, *info)
+	}
+	return services, nil
+}
+
+func inspectService(svc *corev1.Service) (*ServiceInfo, error) {
+	info := &ServiceInfo{
+		Name:         svc.Name,
+		Type:         string(svc.Spec.Type),
+		ClusterIP:    svc.Spec.ClusterIP,
+		ExternalName: svc.Spec.ExternalName,
+		Selector:     svc.Spec.Selector,
+	}
+
+	info.ExternalIPs = append(info.ExternalIPs, svc.Spec.ExternalIPs...)
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			info.ExternalIPs = append(info.ExternalIPs, ingress.IP)
+		} else if ingress.Hostname != "" {
+			info.ExternalIPs = append(info.ExternalIPs, ingress.Hostname)
+		}
+	}
+
+	for _, p := range svc.Spec.Ports {
+		port := fmt.Sprintf("%d->%s/%s", p.Port, p.TargetPort.String(), p.Protocol)
+		if p.Name != "" {
+			port = p.Name + " " + port
+		}
+		if p.NodePort != 0 {
+			port += fmt.Sprintf(" (nodePort %d)", p.NodePort)
+		}
+		info.Ports = append(info.Ports, port)
+	}
+
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		return info, nil
+	}
+
+	if len(svc.Spec.Selector) > 0 {
+		pods, err := clientset.CoreV1().Pods(svc.Namespace).List(context.TODO(), metav1.ListOptions{
+			LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pods: %v", err)
+		}
+		info.MatchedPods = len(pods.Items)
+		if info.MatchedPods == 0 {
+			info.Warnings = append(info.Warnings, "selector matches no pods, the service has no backends")
+		}
+	} else {
+		info.Warnings = append(info.Warnings, "service has no selector, endpoints are managed manually")
+	}
+
+	slices, err := clientset.DiscoveryV1().EndpointSlices(svc.Namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + svc.Name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list endpoint slices: %v", err)
+	}
+
+	for _, slice := range slices.Items {
+		for _, ep := range slice.Endpoints {
+			var pod, node string
+			if ep.TargetRef != nil && ep.TargetRef.Kind == "Pod" {
+				pod = ep.TargetRef.Name
+			}
+			if ep.NodeName != nil {
+				node = *ep.NodeName
+			}
+
+			// a nil ready condition is to be interpreted as ready
+			ready := ep.Conditions.Ready == nil || *ep.Conditions.Ready
+			for _, address := range ep.Addresses {
+				addr := EndpointAddress{Address: address, Pod: pod, Node: node}
+				if ready {
+					info.Ready = append(info.Ready, addr)
+				} else {
+					info.NotReady = append(info.NotReady, addr)
+				}
+			}
+		}
+	}
+
+	if info.MatchedPods > 0 && len(info.Ready) == 0 {
+		info.Warnings = append(info.Warnings, "pods match the selector but none of them is ready")
+	}
+
+	return info, nil
+}
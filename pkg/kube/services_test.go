@@ -0,0 +1,129 @@
+package kube
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func service(name string, selector map[string]string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"},
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeClusterIP,
+			Selector: selector,
+			Ports:    []corev1.ServicePort{{Name: "http", Port: 80, TargetPort: intstr.FromString("http"), Protocol: corev1.ProtocolTCP}},
+		},
+	}
+}
+
+func labeledPod(name, app string) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", Labels: map[string]string{"app": app}}}
+}
+
+// endpointSlice of a service with one endpoint per address, ready is nil when the
+// condition is unknown
+func endpointSlice(serviceName string, ready []*bool, addresses ...string) *discoveryv1.EndpointSlice {
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      serviceName + "-abcde",
+			Namespace: "shop",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: serviceName},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+	}
+	node := "worker-1"
+	for i, address := range addresses {
+		slice.Endpoints = append(slice.Endpoints, discoveryv1.Endpoint{
+			Addresses:  []string{address},
+			Conditions: discoveryv1.EndpointConditions{Ready: ready[i]},
+			TargetRef:  &corev1.ObjectReference{Kind: "Pod", Name: serviceName + "-" + address},
+			NodeName:   &node,
+		})
+	}
+	return slice
+}
+
+func TestInspectServices(t *testing.T) {
+	yes, no := true, false
+	external := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "payments", Namespace: "shop"},
+		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeExternalName, ExternalName: "payments.example.com"},
+	}
+
+	fakeClientset(t,
+		service("cart", map[string]string{"app": "cart"}),
+		labeledPod("cart-1", "cart"),
+		labeledPod("cart-2", "cart"),
+		endpointSlice("cart", []*bool{&yes, &no, nil}, "10.0.0.1", "10.0.0.2", "10.0.0.3"),
+		service("orders", map[string]string{"app": "orders"}),
+		labeledPod("orders-1", "orders"),
+		endpointSlice("orders", []*bool{&no}, "10.0.1.1"),
+		service("ghost", map[string]string{"app": "ghost"}),
+		service("legacy", nil),
+		external,
+	)
+
+	services, err := InspectServices("shop", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := map[string]ServiceInfo{}
+	for _, svc := range services {
+		byName[svc.Name] = svc
+	}
+
+	tests := []struct {
+		name     string
+		matched  int
+		ready    []string
+		notReady []string
+		warning  string
+	}{
+		// a nil ready condition counts as ready
+		{name: "cart", matched: 2, ready: []string{"10.0.0.1", "10.0.0.3"}, notReady: []string{"10.0.0.2"}},
+		{name: "orders", matched: 1, notReady: []string{"10.0.1.1"}, warning: "none of them is ready"},
+		{name: "ghost", warning: "selector matches no pods"},
+		{name: "legacy", warning: "no selector"},
+		{name: "payments"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, found := byName[tt.name]
+			if !found {
+				t.Fatalf("service %s is missing", tt.name)
+			}
+			if svc.MatchedPods != tt.matched {
+				t.Errorf("matched pods = %d, want %d", svc.MatchedPods, tt.matched)
+			}
+			if got := addresses(svc.Ready); !reflect.DeepEqual(got, tt.ready) {
+				t.Errorf("ready = %v, want %v", got, tt.ready)
+			}
+			if got := addresses(svc.NotReady); !reflect.DeepEqual(got, tt.notReady) {
+				t.Errorf("not ready = %v, want %v", got, tt.notReady)
+			}
+			if tt.warning == "" && len(svc.Warnings) > 0 || tt.warning != "" && !strings.Contains(strings.Join(svc.Warnings, "\n"), tt.warning) {
+				t.Errorf("warnings = %v, want %q", svc.Warnings, tt.warning)
+			}
+		})
+	}
+
+	cart := byName["cart"]
+	if !reflect.DeepEqual(cart.Ports, []string{"http 80->http/TCP"}) || cart.Ready[0].Pod != "cart-10.0.0.1" || cart.Ready[0].Node != "worker-1" {
+		t.Errorf("cart ports = %v, first ready endpoint = %+v", cart.Ports, cart.Ready[0])
+	}
+}
+
+func addresses(endpoints []EndpointAddress) []string {
+	var list []string
+	for _, ep := range endpoints {
+		list = append(list, ep.Address)
+	}
+	return list
+}
@@ -126,8 +126,36 @@ There are three possible responses:
 */
 
 func SelectTool(ctx context.Context, llm *openai.LLM, toolListSchema string, query string) (*SelectedToolInfo, error) {
+	return selectTool(ctx, toolSelectionMessages(toolListSchema, query))
+}
+
+// RepairToolSelection asks the model to correct a tool selection whose arguments do not
+// match the inputSchema of the tool, the validation errors are given as feedback
+func RepairToolSelection(ctx context.Context, llm *openai.LLM, toolListSchema string, query string, previous *SelectedToolInfo, violations []string) (*SelectedToolInfo, error) {
+	previousJSON, err := json.Marshal(previous)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal previous selection: %w", err)
+	}
+
+	messages := append(toolSelectionMessages(toolListSchema, query),
+		map[string]string{
+			"role":    "assistant",
+			"content": string(previousJSON),
+		},
+		map[string]string{
+			"role": "user",
+			"content": fmt.Sprintf("The tool_args do not match the inputSchema of the tool %s:\n- %s\n"+
+				"Respond again with the corrected JSON only, using the types declared in the inputSchema. "+
+				"If a required argument cannot be determined from the query, list it in missing_args.",
+				previous.ToolName, strings.Join(violations, "\n- ")),
+		},
+	)
+	return selectTool(ctx, messages)
+}
+
+func toolSelectionMessages(toolListSchema string, query string) []map[string]string {
 	// Construct raw OpenAI-compatible message payload
-	messages := []map[string]string{
+	return []map[string]string{
 		{
 			"role":    "system",
 			"content": toolSelectionPrompt,
@@ -141,7 +169,9 @@ func SelectTool(ctx context.Context, llm *openai.LLM, toolListSchema string, que
 			"content": query,
 		},
 	}
+}
 
+func selectTool(ctx context.Context, messages []map[string]string) (*SelectedToolInfo, error) {
 	// Prepare request body
 	payload := map[string]interface{}{
 		"model":      "gpt-4o",
@@ -24,12 +24,42 @@ var (
 
 	// Cross reference table of tools. toolName -> Tool struct
 	toolsTable = make(map[string]ToolInfo)
+
+	// Tools in the order they were listed by the servers
+	allTools []*protocol.Tool
 )
 
 func GetToolListSchema() string {
 	return toolList
 }
 
+// GetTools returns the tools of all MCP servers
+func GetTools() []*protocol.Tool {
+	return allTools
+}
+
+// GetToolListSchemaFor serializes the tools for which allowed returns true
+func GetToolListSchemaFor(allowed func(toolName string) bool) string {
+	tools := []*protocol.Tool{}
+	for _, tool := range allTools {
+		if allowed(tool.Name) {
+			tools = append(tools, tool)
+		}
+	}
+
+	jsonDoc, err := json.Marshal(struct {
+		Tools []*protocol.Tool `json:"tools"`
+	}{
+		Tools: tools,
+	})
+	if err != nil {
+		// never fall back to the unfiltered list, the user would see tools they may not use
+		log.Printf("ToolList Marshal. Err: %v\n", err)
+		return `{"tools":[]}`
+	}
+	return string(jsonDoc)
+}
+
 func CallTool(ctx context.Context, selectedTool *llm.SelectedToolInfo) (string, error) {
 	toolInfo, ok := toolsTable[selectedTool.ToolName]
 	if !ok {
@@ -94,5 +124,6 @@ func InitalizeTools() {
 	}
 
 	toolList = string(jsonDoc)
+	allTools = allToolsForSchema
 	//log.Printf("ToolList: %s", toolList)
 }
@@ -0,0 +1,299 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"uf/mcp/pkg/llm"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/tmc/langchaingo/llms/openai"
+)
+
+const (
+	// times the model is asked to correct invalid tool arguments
+	DefaultRepairAttempts = 2
+)
+
+var (
+	// asks the model to correct a tool selection, replaced in tests
+	repairToolSelection = llm.RepairToolSelection
+)
+
+// GetTool returns the tool with the given name
+func GetTool(name string) (*protocol.Tool, bool) {
+	toolInfo, ok := toolsTable[name]
+	if !ok {
+		return nil, false
+	}
+	return toolInfo.ToolName, true
+}
+
+// ParseArgs converts key=value pairs into tool arguments, each value is converted to
+// the type its property declares in the inputSchema of the tool
+func ParseArgs(tool *protocol.Tool, pairs []string) (map[string]any, error) {
+	args := map[string]any{}
+	for _, pair := range pairs {
+		key, value, found := strings.Cut(pair, "=")
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid argument '%s', expected key=value", pair)
+		}
+
+		prop, known := tool.InputSchema.Properties[key]
+		if !known {
+			return nil, fmt.Errorf("unknown argument '%s', %s accepts: %s", key, tool.Name, strings.Join(propertyNames(tool), ", "))
+		}
+
+		converted, err := fromString(value, string(prop.Type))
+		if err != nil {
+			return nil, fmt.Errorf("argument '%s': %v", key, err)
+		}
+		args[key] = converted
+	}
+	return args, nil
+}
+
+// ValidateArgs checks arguments against the inputSchema of a tool and returns every violation
+func ValidateArgs(tool *protocol.Tool, args map[string]any) []string {
+	var violations []string
+
+	for _, name := range tool.InputSchema.Required {
+		if _, found := args[name]; !found {
+			violations = append(violations, fmt.Sprintf("missing required argument '%s'", name))
+		}
+	}
+
+	keys := make([]string, 0, len(args))
+	for key := range args {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		prop, known := tool.InputSchema.Properties[key]
+		if !known {
+			violations = append(violations, fmt.Sprintf("unknown argument '%s'", key))
+			continue
+		}
+		if !hasType(args[key], string(prop.Type)) {
+			violations = append(violations, fmt.Sprintf("argument '%s' must be of type %s, got %s", key, prop.Type, jsonType(args[key])))
+		}
+	}
+	return violations
+}
+
+// CoerceArgs returns a copy of the arguments with values converted to the type of their
+// property where the conversion is lossless, e.g. "5" for a number, 3.0 for an integer
+// or "true" for a boolean. Null values of optional arguments are dropped.
+func CoerceArgs(tool *protocol.Tool, args map[string]any) map[string]any {
+	coerced := make(map[string]any, len(args))
+	for key, value := range args {
+		prop, known := tool.InputSchema.Properties[key]
+		if !known {
+			coerced[key] = value
+			continue
+		}
+		if value == nil && !isRequired(tool, key) {
+			continue
+		}
+		coerced[key] = coerce(value, string(prop.Type))
+	}
+	return coerced
+}
+
+// CheckToolArgs coerces and validates the arguments of a tool selected by the model. While
+// they violate the inputSchema the model is re-prompted with the violations, at most
+// maxRepairs times. The last selection is returned along with an error when it is still invalid.
+func CheckToolArgs(ctx context.Context, model *openai.LLM, toolListSchema, query string, selected *llm.SelectedToolInfo, maxRepairs int) (*llm.SelectedToolInfo, error) {
+	for attempt := 0; ; attempt++ {
+		// nothing to call, the caller reports missing arguments or answers without a tool
+		if selected.ToolName == "none" || len(selected.MissingArgs) > 0 {
+			return selected, nil
+		}
+
+		var violations []string
+		if tool, found := GetTool(selected.ToolName); found {
+			selected.ToolArgs = CoerceArgs(tool, selected.ToolArgs)
+			violations = ValidateArgs(tool, selected.ToolArgs)
+		} else {
+			violations = []string{fmt.Sprintf("unknown tool '%s', select a tool of the list", selected.ToolName)}
+		}
+		if len(violations) == 0 {
+			return selected, nil
+		}
+
+		if attempt >= maxRepairs {
+			return selected, fmt.Errorf("invalid arguments for tool %s: %s", selected.ToolName, strings.Join(violations, "; "))
+		}
+
+		log.Printf("Tool selection %s violates the inputSchema, repair attempt %d/%d: %s", selected.ToolName, attempt+1, maxRepairs, strings.Join(violations, "; "))
+		repaired, err := repairToolSelection(ctx, model, toolListSchema, query, selected, violations)
+		if err != nil {
+			return selected, fmt.Errorf("repair of tool arguments failed: %v", err)
+		}
+		selected = repaired
+	}
+}
+
+// ArgsUsage describes the arguments of a tool, e.g. namespace:string* replicas:integer,
+// required arguments are marked with *
+func ArgsUsage(tool *protocol.Tool) string {
+	required := map[string]bool{}
+	for _, name := range tool.InputSchema.Required {
+		required[name] = true
+	}
+
+	var parts []string
+	for _, name := range propertyNames(tool) {
+		part := fmt.Sprintf("%s:%s", name, tool.InputSchema.Properties[name].Type)
+		if required[name] {
+			part += "*"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " ")
+}
+
+func propertyNames(tool *protocol.Tool) []string {
+	names := make([]string, 0, len(tool.InputSchema.Properties))
+	for name := range tool.InputSchema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func isRequired(tool *protocol.Tool, name string) bool {
+	for _, required := range tool.InputSchema.Required {
+		if required == name {
+			return true
+		}
+	}
+	return false
+}
+
+// coerce converts a JSON decoded value to schemaType when that is safe, otherwise the
+// value is returned unchanged and left to the validation
+func coerce(value any, schemaType string) any {
+	if hasType(value, schemaType) {
+		if f, ok := value.(float64); ok && schemaType == "integer" {
+			return int64(f)
+		}
+		return value
+	}
+
+	switch v := value.(type) {
+	case string:
+		s := strings.TrimSpace(v)
+		switch schemaType {
+		case "number", "integer", "boolean":
+			if converted, err := fromString(s, schemaType); err == nil {
+				return converted
+			}
+		case "array", "object":
+			if converted, err := fromString(s, schemaType); err == nil && hasType(converted, schemaType) {
+				return converted
+			}
+		}
+	case float64:
+		if schemaType == "string" {
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		}
+	case bool:
+		if schemaType == "string" {
+			return strconv.FormatBool(v)
+		}
+	}
+	return value
+}
+
+func fromString(value, schemaType string) (any, error) {
+	switch schemaType {
+	case "number":
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("'%s' is not a number", value)
+		}
+		return f, nil
+	case "integer":
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not an integer", value)
+		}
+		return i, nil
+	case "boolean":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a boolean", value)
+		}
+		return b, nil
+	case "array", "object":
+		var v any
+		if err := json.Unmarshal([]byte(value), &v); err != nil {
+			return nil, fmt.Errorf("'%s' is not a JSON %s", value, schemaType)
+		}
+		return v, nil
+	default:
+		return value, nil
+	}
+}
+
+// hasType reports whether a JSON decoded value matches a JSON Schema type
+func hasType(value any, schemaType string) bool {
+	switch schemaType {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		switch value.(type) {
+		case float64, float32, int, int64:
+			return true
+		}
+		return false
+	case "integer":
+		switch v := value.(type) {
+		case int, int64:
+			return true
+		case float64:
+			// whole numbers beyond the int64 range can't be converted without loss
+			return v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64
+		}
+		return false
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	default:
+		// no or unknown type, anything goes
+		return true
+	}
+}
+
+func jsonType(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64, float32, int, int64:
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
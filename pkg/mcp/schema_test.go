@@ -0,0 +1,308 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"uf/mcp/pkg/llm"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+	"github.com/tmc/langchaingo/llms/openai"
+)
+
+type scalerArgs struct {
+	Namespace string   `json:"namespace" description:"Namespace of the deployment" required:"true"`
+	Replicas  int      `json:"replicas" description:"Number of replicas" required:"true"`
+	Ratio     float64  `json:"ratio" description:"Optional share of the replicas to restart"`
+	DryRun    bool     `json:"dryRun" description:"Optional, only validate the change"`
+	Names     []string `json:"names" description:"Optional deployments to scale"`
+}
+
+func testTool(t *testing.T) *protocol.Tool {
+	t.Helper()
+
+	tool, err := protocol.NewTool("Scaler", "Scales deployments", scalerArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tool
+}
+
+func TestParseArgs(t *testing.T) {
+	tool := testTool(t)
+
+	tests := []struct {
+		name    string
+		pairs   []string
+		want    map[string]any
+		wantErr bool
+	}{
+		{"no arguments", nil, map[string]any{}, false},
+		{
+			"converted to the property types",
+			[]string{"namespace=shop", "replicas=3", "ratio=0.5", "dryRun=true", `names=["cart","web"]`},
+			map[string]any{"namespace": "shop", "replicas": int64(3), "ratio": 0.5, "dryRun": true, "names": []any{"cart", "web"}},
+			false,
+		},
+		{"value containing =", []string{"namespace=a=b"}, map[string]any{"namespace": "a=b"}, false},
+		{"empty value", []string{"namespace="}, map[string]any{"namespace": ""}, false},
+		{"missing =", []string{"namespace"}, nil, true},
+		{"missing key", []string{"=shop"}, nil, true},
+		{"unknown argument", []string{"replica=3"}, nil, true},
+		{"invalid integer", []string{"replicas=three"}, nil, true},
+		{"invalid boolean", []string{"dryRun=maybe"}, nil, true},
+		{"invalid array", []string{"names=cart"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseArgs(tool, tt.pairs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseArgs() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateArgs(t *testing.T) {
+	tool := testTool(t)
+
+	tests := []struct {
+		name string
+		args map[string]any
+		want []string
+	}{
+		{"valid", map[string]any{"namespace": "shop", "replicas": 3.0}, nil},
+		{"valid optional arguments", map[string]any{"namespace": "shop", "replicas": int64(3), "ratio": 1, "dryRun": false, "names": []any{"cart"}}, nil},
+		{
+			"missing required arguments",
+			map[string]any{"replicas": 2.0},
+			[]string{"missing required argument 'namespace'"},
+		},
+		{
+			"every violation, sorted by argument",
+			map[string]any{"namespace": "shop", "replicas": "3", "extra": true, "names": "cart"},
+			[]string{
+				"unknown argument 'extra'",
+				"argument 'names' must be of type array, got string",
+				"argument 'replicas' must be of type integer, got string",
+			},
+		},
+		{"fractional integer", map[string]any{"namespace": "shop", "replicas": 2.5}, []string{"argument 'replicas' must be of type integer, got number"}},
+		{"integer beyond int64", map[string]any{"namespace": "shop", "replicas": 1e20}, []string{"argument 'replicas' must be of type integer, got number"}},
+		{"null", map[string]any{"namespace": nil, "replicas": 1.0}, []string{"argument 'namespace' must be of type string, got null"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidateArgs(tool, tt.args); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromString(t *testing.T) {
+	tests := []struct {
+		value      string
+		schemaType string
+		want       any
+		wantErr    bool
+	}{
+		{"shop", "string", "shop", false},
+		{"42", "", "42", false},
+		{"1e3", "number", 1000.0, false},
+		{"-0.25", "number", -0.25, false},
+		{"NaN", "number", nil, true},
+		{"Inf", "number", nil, true},
+		{"five", "number", nil, true},
+		{"-7", "integer", int64(-7), false},
+		{"3.5", "integer", nil, true},
+		{"9223372036854775808", "integer", nil, true},
+		{"false", "boolean", false, false},
+		{"yes", "boolean", nil, true},
+		{"[1, 2]", "array", []any{1.0, 2.0}, false},
+		{`{"app": "cart"}`, "object", map[string]any{"app": "cart"}, false},
+		{"app=cart", "object", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.schemaType+"/"+tt.value, func(t *testing.T) {
+			got, err := fromString(tt.value, tt.schemaType)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("fromString() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fromString() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCoerce(t *testing.T) {
+	tests := []struct {
+		value      any
+		schemaType string
+		want       any
+	}{
+		{3.0, "integer", int64(3)},
+		{-2.0, "integer", int64(-2)},
+		{" 12 ", "integer", int64(12)},
+		{"5", "number", 5.0},
+		{"true", "boolean", true},
+		{`["a"]`, "array", []any{"a"}},
+		{`{"a": 1}`, "object", map[string]any{"a": 1.0}},
+		{7.5, "string", "7.5"},
+		{false, "string", "false"},
+		{"shop", "string", "shop"},
+		// lossy or impossible conversions are left to the validation
+		{2.5, "integer", 2.5},
+		{1e20, "integer", 1e20},
+		{-1e19, "integer", -1e19},
+		{math.Inf(1), "integer", math.Inf(1)},
+		{"9223372036854775808", "integer", "9223372036854775808"},
+		{"NaN", "number", "NaN"},
+		{"yes", "boolean", "yes"},
+		{`{"a": 1}`, "array", `{"a": 1}`},
+		{"[1]", "object", "[1]"},
+		{1.0, "boolean", 1.0},
+		{nil, "string", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v as %s", tt.value, tt.schemaType), func(t *testing.T) {
+			if got := coerce(tt.value, tt.schemaType); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("coerce() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCoerceArgs(t *testing.T) {
+	tool := testTool(t)
+
+	args := map[string]any{"namespace": "shop", "replicas": "4", "ratio": nil, "extra": "kept"}
+	got := CoerceArgs(tool, args)
+	want := map[string]any{"namespace": "shop", "replicas": int64(4), "extra": "kept"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CoerceArgs() = %#v, want %#v", got, want)
+	}
+	if args["replicas"] != "4" {
+		t.Errorf("CoerceArgs() changed its input")
+	}
+
+	// required arguments keep their null, the validation reports it
+	if got := CoerceArgs(tool, map[string]any{"namespace": nil}); len(got) != 1 {
+		t.Errorf("CoerceArgs() dropped a required null argument: %#v", got)
+	}
+}
+
+func TestCheckToolArgs(t *testing.T) {
+	tool := testTool(t)
+	toolsTable[tool.Name] = ToolInfo{ToolName: tool}
+	defer delete(toolsTable, tool.Name)
+	defer func(repair func(context.Context, *openai.LLM, string, string, *llm.SelectedToolInfo, []string) (*llm.SelectedToolInfo, error)) {
+		repairToolSelection = repair
+	}(repairToolSelection)
+
+	valid := &llm.SelectedToolInfo{ToolName: "Scaler", ToolArgs: map[string]any{"namespace": "shop", "replicas": 2.0}}
+	invalid := &llm.SelectedToolInfo{ToolName: "Scaler", ToolArgs: map[string]any{"namespace": "shop", "replicas": "two"}}
+
+	tests := []struct {
+		name     string
+		selected *llm.SelectedToolInfo
+		// selections returned by the stubbed repairs, in order
+		repairs   []*llm.SelectedToolInfo
+		repairErr error
+		want      map[string]any
+		calls     int
+		wantErr   string
+	}{
+		{
+			name:     "no tool",
+			selected: &llm.SelectedToolInfo{ToolName: "none"},
+		},
+		{
+			name:     "missing arguments are asked to the user",
+			selected: &llm.SelectedToolInfo{ToolName: "Scaler", MissingArgs: []string{"namespace"}},
+		},
+		{
+			name:     "coerced without repair",
+			selected: &llm.SelectedToolInfo{ToolName: "Scaler", ToolArgs: map[string]any{"namespace": "shop", "replicas": "3"}},
+			want:     map[string]any{"namespace": "shop", "replicas": int64(3)},
+		},
+		{
+			name:     "repaired",
+			selected: invalid,
+			repairs:  []*llm.SelectedToolInfo{valid},
+			want:     map[string]any{"namespace": "shop", "replicas": int64(2)},
+			calls:    1,
+		},
+		{
+			name:     "unknown tool repaired",
+			selected: &llm.SelectedToolInfo{ToolName: "Scale", ToolArgs: map[string]any{"namespace": "shop", "replicas": 2.0}},
+			repairs:  []*llm.SelectedToolInfo{valid},
+			want:     map[string]any{"namespace": "shop", "replicas": int64(2)},
+			calls:    1,
+		},
+		{
+			name:     "still invalid after the last repair",
+			selected: invalid,
+			repairs:  []*llm.SelectedToolInfo{invalid, invalid},
+			calls:    2,
+			wantErr:  "argument 'replicas' must be of type integer, got string",
+		},
+		{
+			name:      "repair failure",
+			selected:  invalid,
+			repairErr: fmt.Errorf("LLM unavailable"),
+			calls:     1,
+			wantErr:   "LLM unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			repairToolSelection = func(ctx context.Context, model *openai.LLM, toolListSchema, query string, previous *llm.SelectedToolInfo, violations []string) (*llm.SelectedToolInfo, error) {
+				calls++
+				if len(violations) == 0 {
+					t.Errorf("repair requested without violations")
+				}
+				if tt.repairErr != nil {
+					return nil, tt.repairErr
+				}
+				// copies, the selection is coerced in place
+				repaired := *tt.repairs[calls-1]
+				repaired.ToolArgs = map[string]any{}
+				for k, v := range tt.repairs[calls-1].ToolArgs {
+					repaired.ToolArgs[k] = v
+				}
+				return &repaired, nil
+			}
+
+			got, err := CheckToolArgs(context.Background(), nil, "{}", "scale the shop", tt.selected, 2)
+			if calls != tt.calls {
+				t.Errorf("repair called %d times, want %d", calls, tt.calls)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("CheckToolArgs() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CheckToolArgs() error = %v", err)
+			}
+			if tt.want != nil && !reflect.DeepEqual(got.ToolArgs, tt.want) {
+				t.Errorf("CheckToolArgs() arguments = %#v, want %#v", got.ToolArgs, tt.want)
+			}
+		})
+	}
+}
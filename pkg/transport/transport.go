@@ -2,12 +2,15 @@ package transport
 
 import (
 	"bytes"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"net/http/httputil"
+	"os"
 	"time"
 )
 
@@ -26,6 +29,40 @@ func NewCustomTransport() *CustomTransport {
 	return &CustomTransport{transport: defTransport}
 }
 
+// ConfigureTLS sets the CA bundle used to verify the server (optional) and the client
+// certificate presented for mutual TLS (optional)
+func (ct *CustomTransport) ConfigureTLS(caFile, certFile, keyFile string) error {
+	httpTransport, ok := ct.transport.(*http.Transport)
+	if !ok {
+		return fmt.Errorf("unsupported transport %T", ct.transport)
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return fmt.Errorf("failed to read CA bundle: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificate found in CA bundle '%s'", caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	httpTransport.TLSClientConfig = tlsConfig
+	return nil
+}
+
 func (ct *CustomTransport) RoundTrip(req *http.Request) (resp *http.Response, err error) {
 	// alter the body if requested..
 
@@ -0,0 +1,56 @@
+package webauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCORS(t *testing.T) {
+	called := false
+	handler := CORS([]string{"https://chat.example.com"}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name        string
+		method      string
+		origin      string
+		allowOrigin string
+		status      int
+		called      bool
+	}{
+		{"same origin", http.MethodGet, "", "", http.StatusOK, true},
+		{"allowed origin", http.MethodPost, "https://chat.example.com", "https://chat.example.com", http.StatusOK, true},
+		{"other origin", http.MethodPost, "https://evil.example.com", "", http.StatusOK, true},
+		{"preflight of allowed origin", http.MethodOptions, "https://chat.example.com", "https://chat.example.com", http.StatusNoContent, false},
+		{"preflight of other origin", http.MethodOptions, "https://evil.example.com", "", http.StatusNoContent, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called = false
+			req := httptest.NewRequest(tt.method, "/chat", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status || called != tt.called {
+				t.Errorf("status %d, handler called %v, want %d, %v", rec.Code, called, tt.status, tt.called)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.allowOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.allowOrigin)
+			}
+			credentials := rec.Header().Get("Access-Control-Allow-Credentials")
+			if (tt.allowOrigin != "") != (credentials == "true") {
+				t.Errorf("Access-Control-Allow-Credentials = %q with allowed origin %q", credentials, tt.allowOrigin)
+			}
+			if rec.Header().Get("Vary") != "Origin" {
+				t.Errorf("responses must vary on Origin")
+			}
+		})
+	}
+}
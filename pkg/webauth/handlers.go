@@ -0,0 +1,73 @@
+package webauth
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+type credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// InfoHandler returns the login methods and the current user, if logged in
+func (m *Manager) InfoHandler(w http.ResponseWriter, r *http.Request) {
+	info := struct {
+		Enabled bool     `json:"enabled"`
+		Methods []string `json:"methods"`
+		User    *User    `json:"user"`
+	}{
+		Enabled: m.Enabled(),
+		Methods: m.Methods(),
+	}
+	if user, ok := m.currentUser(r); ok {
+		info.User = user
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(info)
+}
+
+// LoginHandler logs in an htpasswd user with a JSON {"username", "password"} body
+func (m *Manager) LoginHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if m.users == nil {
+		writeError(w, http.StatusNotFound, "password login is not configured")
+		return
+	}
+
+	var creds credentials
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&creds); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	user, err := m.checkPassword(creds.Username, creds.Password)
+	if err != nil {
+		log.Printf("Failed login for user '%s' from %s", creds.Username, r.RemoteAddr)
+		writeError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err := m.startSession(w, *user); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// LogoutHandler ends the session of the caller
+func (m *Manager) LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	m.endSession(w, r)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,70 @@
+package webauth
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// compared against when the user is unknown, so that the response time does not
+// reveal which user names exist
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+
+// loadHtpasswd reads user:hash lines as written by `htpasswd -B`
+func loadHtpasswd(file string) (map[string][]byte, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read htpasswd file: %v", err)
+	}
+	defer f.Close()
+
+	users := map[string][]byte{}
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, hash, found := strings.Cut(line, ":")
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid entry on line %d of '%s'", lineNo, file)
+		}
+		if !strings.HasPrefix(hash, "$2") {
+			return nil, fmt.Errorf("user '%s' in '%s' is not bcrypt hashed, use htpasswd -B", name, file)
+		}
+		users[name] = []byte(hash)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read htpasswd file: %v", err)
+	}
+	return users, nil
+}
+
+// checkPassword verifies the credentials of an htpasswd user
+func (m *Manager) checkPassword(name, password string) (*User, error) {
+	hash, found := m.users[name]
+	if !found {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return nil, fmt.Errorf("invalid user name or password")
+	}
+	if err := bcrypt.CompareHashAndPassword(hash, []byte(password)); err != nil {
+		return nil, fmt.Errorf("invalid user name or password")
+	}
+
+	user := &User{Name: name, Subject: name, Provider: "htpasswd"}
+	for group, members := range m.config.Groups {
+		for _, member := range members {
+			if member == name {
+				user.Groups = append(user.Groups, group)
+				break
+			}
+		}
+	}
+	sort.Strings(user.Groups)
+	return user, nil
+}
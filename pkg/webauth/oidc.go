@@ -0,0 +1,196 @@
+package webauth
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+const (
+	stateCookie = "mcp_oidc_state"
+	nonceCookie = "mcp_oidc_nonce"
+
+	// time a user has to complete the login at the identity provider
+	loginTimeout = 10 * time.Minute
+)
+
+// OIDCConfig of an OpenID Connect identity provider using the authorization code flow
+type OIDCConfig struct {
+	Issuer       string   `json:"issuer"`
+	ClientID     string   `json:"clientID"`
+	ClientSecret string   `json:"clientSecret"`
+	RedirectURL  string   `json:"redirectURL"`
+	Scopes       []string `json:"scopes"`
+	// claim holding the user name, defaults to preferred_username with a fallback to email and sub
+	UsernameClaim string `json:"usernameClaim"`
+	// claim holding the groups of the user, defaults to groups
+	GroupsClaim string `json:"groupsClaim"`
+}
+
+type oidcProvider struct {
+	config   *OIDCConfig
+	oauth2   oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+func newOIDCProvider(config *OIDCConfig) (*oidcProvider, error) {
+	if config.Issuer == "" || config.ClientID == "" || config.RedirectURL == "" {
+		return nil, fmt.Errorf("oidc needs an issuer, a clientID and a redirectURL")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// discovery of the provider endpoints and signing keys
+	provider, err := oidc.NewProvider(ctx, config.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover oidc issuer '%s': %v", config.Issuer, err)
+	}
+
+	scopes := config.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"profile", "email", "groups"}
+	}
+
+	log.Printf("OIDC login enabled with issuer %s", config.Issuer)
+	return &oidcProvider{
+		config: config,
+		oauth2: oauth2.Config{
+			ClientID:     config.ClientID,
+			ClientSecret: config.ClientSecret,
+			RedirectURL:  config.RedirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       append([]string{oidc.ScopeOpenID}, scopes...),
+		},
+		verifier: provider.Verifier(&oidc.Config{ClientID: config.ClientID}),
+	}, nil
+}
+
+// OIDCLoginHandler redirects the browser to the identity provider
+func (m *Manager) OIDCLoginHandler(w http.ResponseWriter, r *http.Request) {
+	if m.oidc == nil {
+		writeError(w, http.StatusNotFound, "oidc login is not configured")
+		return
+	}
+
+	state, err := randomString()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	nonce, err := randomString()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	http.SetCookie(w, m.cookie(stateCookie, state, loginTimeout))
+	http.SetCookie(w, m.cookie(nonceCookie, nonce, loginTimeout))
+	http.Redirect(w, r, m.oidc.oauth2.AuthCodeURL(state, oidc.Nonce(nonce)), http.StatusFound)
+}
+
+// OIDCCallbackHandler exchanges the authorization code, verifies the ID token and starts a session
+func (m *Manager) OIDCCallbackHandler(w http.ResponseWriter, r *http.Request) {
+	if m.oidc == nil {
+		writeError(w, http.StatusNotFound, "oidc login is not configured")
+		return
+	}
+
+	if e := r.URL.Query().Get("error"); e != "" {
+		writeError(w, http.StatusUnauthorized, fmt.Sprintf("login failed: %s %s", e, r.URL.Query().Get("error_description")))
+		return
+	}
+
+	state, err := r.Cookie(stateCookie)
+	if err != nil || r.URL.Query().Get("state") != state.Value {
+		writeError(w, http.StatusBadRequest, "invalid login state, please retry")
+		return
+	}
+	nonce, err := r.Cookie(nonceCookie)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid login state, please retry")
+		return
+	}
+	http.SetCookie(w, m.cookie(stateCookie, "", -1))
+	http.SetCookie(w, m.cookie(nonceCookie, "", -1))
+
+	token, err := m.oidc.oauth2.Exchange(r.Context(), r.URL.Query().Get("code"))
+	if err != nil {
+		log.Printf("OIDC code exchange failed: %v", err)
+		writeError(w, http.StatusUnauthorized, "login failed")
+		return
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "login failed, no id_token returned")
+		return
+	}
+
+	idToken, err := m.oidc.verifier.Verify(r.Context(), rawIDToken)
+	if err != nil {
+		log.Printf("OIDC id_token verification failed: %v", err)
+		writeError(w, http.StatusUnauthorized, "login failed")
+		return
+	}
+	if idToken.Nonce != nonce.Value {
+		writeError(w, http.StatusUnauthorized, "login failed, invalid nonce")
+		return
+	}
+
+	user, err := m.oidc.userOf(idToken)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err := m.startSession(w, *user); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	http.Redirect(w, r, "/", http.StatusFound)
+}
+
+func (p *oidcProvider) userOf(idToken *oidc.IDToken) (*User, error) {
+	claims := map[string]any{}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("invalid id_token claims: %v", err)
+	}
+
+	usernameClaims := []string{"preferred_username", "email", "sub"}
+	if p.config.UsernameClaim != "" {
+		usernameClaims = []string{p.config.UsernameClaim}
+	}
+
+	user := &User{Subject: idToken.Subject, Provider: "oidc"}
+	for _, claim := range usernameClaims {
+		if name, ok := claims[claim].(string); ok && name != "" {
+			user.Name = name
+			break
+		}
+	}
+	if user.Name == "" {
+		return nil, fmt.Errorf("id_token has no user name claim %v", usernameClaims)
+	}
+	if user.Subject == "" {
+		return nil, fmt.Errorf("id_token has no sub claim")
+	}
+
+	groupsClaim := p.config.GroupsClaim
+	if groupsClaim == "" {
+		groupsClaim = "groups"
+	}
+	if groups, ok := claims[groupsClaim].([]any); ok {
+		for _, g := range groups {
+			if group, ok := g.(string); ok {
+				user.Groups = append(user.Groups, group)
+			}
+		}
+	}
+	return user, nil
+}
@@ -0,0 +1,89 @@
+package webauth
+
+import (
+	"fmt"
+	"path"
+)
+
+// Role grants the users and groups it matches the tools it lists. Users, groups and tools
+// are glob patterns, e.g. "Show*". Users are matched on their provider qualified ID, e.g.
+// htpasswd:alice or oidc:<sub>, as user names are not unique across providers.
+type Role struct {
+	Name   string   `json:"name"`
+	Users  []string `json:"users"`
+	Groups []string `json:"groups"`
+	Tools  []string `json:"tools"`
+	// members may query the audit log
+	AuditLog bool `json:"auditLog"`
+}
+
+func validateRoles(roles []Role) error {
+	for i, role := range roles {
+		for _, patterns := range [][]string{role.Users, role.Groups, role.Tools} {
+			for _, pattern := range patterns {
+				if _, err := path.Match(pattern, ""); err != nil {
+					return fmt.Errorf("invalid pattern '%s' in role %d: %v", pattern, i+1, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// CanUseTool reports whether one of the roles of the user grants the tool. Without
+// roles every user may use every tool.
+func (m *Manager) CanUseTool(user *User, toolName string) bool {
+	if len(m.config.Roles) == 0 {
+		return true
+	}
+	for _, role := range m.rolesOf(user) {
+		if matchesAny(role.Tools, toolName) {
+			return true
+		}
+	}
+	return false
+}
+
+// CanReadAuditLog reports whether one of the roles of the user grants the audit log.
+// Without roles every user may read it.
+func (m *Manager) CanReadAuditLog(user *User) bool {
+	if len(m.config.Roles) == 0 {
+		return true
+	}
+	for _, role := range m.rolesOf(user) {
+		if role.AuditLog {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Manager) rolesOf(user *User) []Role {
+	if user == nil {
+		return nil
+	}
+
+	var roles []Role
+	for _, role := range m.config.Roles {
+		member := matchesAny(role.Users, user.ID())
+		for _, group := range user.Groups {
+			if member {
+				break
+			}
+			member = matchesAny(role.Groups, group)
+		}
+		if member {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+func matchesAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
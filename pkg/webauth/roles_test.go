@@ -0,0 +1,58 @@
+package webauth
+
+import "testing"
+
+func TestCanUseTool(t *testing.T) {
+	m := &Manager{config: Config{Roles: []Role{
+		{Name: "viewer", Users: []string{"*"}, Tools: []string{"List*", "Show*"}},
+		{Name: "operator", Groups: []string{"sre"}, Tools: []string{"*"}, AuditLog: true},
+		{Name: "restarter", Users: []string{"htpasswd:bob"}, Tools: []string{"ServiceRestarter"}},
+		{Name: "drainer", Users: []string{"oidc:CgVkYXZl*"}, Tools: []string{"NodeDrain"}},
+	}}}
+
+	alice := &User{Name: "alice", Groups: []string{"dev", "sre"}, Subject: "CgVhbGljZQ", Provider: "oidc"}
+	bob := &User{Name: "bob", Groups: []string{"dev"}, Subject: "bob", Provider: "htpasswd"}
+	carol := &User{Name: "carol", Subject: "carol", Provider: "htpasswd"}
+	// same name as the htpasswd user, but another identity
+	oidcBob := &User{Name: "bob", Subject: "CgNib2I", Provider: "oidc"}
+	dave := &User{Name: "dave", Subject: "CgVkYXZlEgVsb2NhbA", Provider: "oidc"}
+
+	tests := []struct {
+		user    *User
+		tool    string
+		allowed bool
+	}{
+		{alice, "NodeDrain", true},
+		{alice, "ListPods", true},
+		{bob, "ServiceRestarter", true},
+		{bob, "ShowSecrets", true},
+		{bob, "NodeDrain", false},
+		{carol, "ListPods", true},
+		{carol, "ServiceRestarter", false},
+		{oidcBob, "ServiceRestarter", false},
+		{oidcBob, "ListPods", true},
+		{dave, "NodeDrain", true},
+		{carol, "NodeDrain", false},
+		{nil, "ListPods", false},
+	}
+
+	for _, tt := range tests {
+		name := "nil"
+		if tt.user != nil {
+			name = tt.user.ID()
+		}
+		if got := m.CanUseTool(tt.user, tt.tool); got != tt.allowed {
+			t.Errorf("CanUseTool(%s, %s) = %v, want %v", name, tt.tool, got, tt.allowed)
+		}
+	}
+
+	if !m.CanReadAuditLog(alice) || m.CanReadAuditLog(bob) {
+		t.Errorf("only members of the operator role may read the audit log")
+	}
+
+	// without roles every user may use every tool
+	open := &Manager{}
+	if !open.CanUseTool(carol, "NodeDrain") || !open.CanReadAuditLog(nil) {
+		t.Errorf("a manager without roles must allow everything")
+	}
+}
@@ -0,0 +1,265 @@
+package webauth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+type contextKey string
+
+const (
+	userKey contextKey = "user"
+
+	sessionCookie     = "mcp_session"
+	defaultSessionTTL = 8 * time.Hour
+)
+
+// Config of the chat client login
+//
+//	htpasswd: /etc/mcp/users.htpasswd
+//	groups:
+//	  sre: [alice]
+//	oidc:
+//	  issuer: http://127.0.0.1:5556/dex
+//	  clientID: mcp-chat
+//	  clientSecret: <secret>
+//	  redirectURL: http://localhost:8080/auth/oidc/callback
+//	roles:
+//	  - name: viewer
+//	    users: ["*"]
+//	    tools: ["List*", "Show*"]
+//	  - name: operator
+//	    groups: [sre]
+//	    tools: ["*"]
+//	    auditLog: true
+//	  - name: restarter
+//	    users: ["htpasswd:bob", "oidc:CgRjYXJvbBIFbG9jYWw"]
+//	    tools: [ServiceRestarter]
+type Config struct {
+	// htpasswd file of local users, bcrypt hashes only
+	Htpasswd string `json:"htpasswd"`
+	// groups of the htpasswd users, group -> user names
+	Groups map[string][]string `json:"groups"`
+	OIDC   *OIDCConfig         `json:"oidc"`
+	// lifetime of a login, e.g. 8h
+	SessionTTL string `json:"sessionTTL"`
+	// set the Secure flag on cookies, required when the client is served over https
+	SecureCookies bool   `json:"secureCookies"`
+	Roles         []Role `json:"roles"`
+}
+
+type User struct {
+	Name   string   `json:"name"`
+	Groups []string `json:"groups,omitempty"`
+	// stable identifier within the provider, the OIDC sub claim or the htpasswd user name
+	Subject  string `json:"subject"`
+	Provider string `json:"provider"`
+}
+
+// ID identifies the user across providers, e.g. oidc:<sub> or htpasswd:alice. User
+// names are not unique: an htpasswd and an OIDC user may both be called alice.
+func (u *User) ID() string {
+	return u.Provider + ":" + u.Subject
+}
+
+type session struct {
+	user    User
+	expires time.Time
+}
+
+// Manager authenticates chat users and keeps their sessions in memory
+type Manager struct {
+	config   Config
+	ttl      time.Duration
+	users    map[string][]byte
+	oidc     *oidcProvider
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+// NewManager loads the login configuration, an empty configFile disables login and
+// every caller is treated as anonymous with access to all tools
+func NewManager(configFile string) (*Manager, error) {
+	m := &Manager{ttl: defaultSessionTTL, sessions: map[string]*session{}}
+	if configFile == "" {
+		return m, nil
+	}
+
+	content, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read login config: %v", err)
+	}
+	if err := yaml.UnmarshalStrict(content, &m.config); err != nil {
+		return nil, fmt.Errorf("invalid login config '%s': %v", configFile, err)
+	}
+
+	if m.config.SessionTTL != "" {
+		if m.ttl, err = time.ParseDuration(m.config.SessionTTL); err != nil {
+			return nil, fmt.Errorf("invalid sessionTTL '%s': %v", m.config.SessionTTL, err)
+		}
+	}
+
+	if err := validateRoles(m.config.Roles); err != nil {
+		return nil, err
+	}
+
+	if m.config.Htpasswd != "" {
+		if m.users, err = loadHtpasswd(m.config.Htpasswd); err != nil {
+			return nil, err
+		}
+	}
+
+	if m.config.OIDC != nil {
+		if m.oidc, err = newOIDCProvider(m.config.OIDC); err != nil {
+			return nil, err
+		}
+	}
+
+	if !m.Enabled() {
+		return nil, fmt.Errorf("login config '%s' defines neither htpasswd nor oidc", configFile)
+	}
+	return m, nil
+}
+
+// Enabled reports whether users have to log in
+func (m *Manager) Enabled() bool {
+	return m.users != nil || m.oidc != nil
+}
+
+// Methods lists the login methods offered to the UI
+func (m *Manager) Methods() []string {
+	methods := []string{}
+	if m.users != nil {
+		methods = append(methods, "password")
+	}
+	if m.oidc != nil {
+		methods = append(methods, "oidc")
+	}
+	return methods
+}
+
+// RequireUser rejects requests without a valid session and stores the user in the request context
+func (m *Manager) RequireUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		user, ok := m.currentUser(r)
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "login required")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithUser(r.Context(), user)))
+	})
+}
+
+// currentUser returns the user of the session cookie, or anonymous when login is disabled
+func (m *Manager) currentUser(r *http.Request) (*User, bool) {
+	if !m.Enabled() {
+		return &User{Name: "anonymous", Subject: "anonymous", Provider: "none"}, true
+	}
+
+	cookie, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return nil, false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, found := m.sessions[cookie.Value]
+	if !found {
+		return nil, false
+	}
+	if time.Now().After(s.expires) {
+		delete(m.sessions, cookie.Value)
+		return nil, false
+	}
+	user := s.user
+	return &user, true
+}
+
+// startSession creates a session for an authenticated user and sets the session cookie
+func (m *Manager) startSession(w http.ResponseWriter, user User) error {
+	id, err := randomString()
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	now := time.Now()
+	// drop expired sessions while we hold the lock
+	for key, s := range m.sessions {
+		if now.After(s.expires) {
+			delete(m.sessions, key)
+		}
+	}
+	m.sessions[id] = &session{user: user, expires: now.Add(m.ttl)}
+	m.mu.Unlock()
+
+	http.SetCookie(w, m.cookie(sessionCookie, id, m.ttl))
+	log.Printf("User %s logged in via %s", user.Name, user.Provider)
+	return nil
+}
+
+func (m *Manager) endSession(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie(sessionCookie); err == nil {
+		m.mu.Lock()
+		delete(m.sessions, cookie.Value)
+		m.mu.Unlock()
+	}
+	http.SetCookie(w, m.cookie(sessionCookie, "", -1))
+}
+
+func (m *Manager) cookie(name, value string, ttl time.Duration) *http.Cookie {
+	c := &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   m.config.SecureCookies,
+		SameSite: http.SameSiteLaxMode,
+	}
+	if ttl < 0 {
+		c.MaxAge = -1
+	} else {
+		c.MaxAge = int(ttl.Seconds())
+	}
+	return c
+}
+
+func WithUser(ctx context.Context, user *User) context.Context {
+	return context.WithValue(ctx, userKey, user)
+}
+
+// UserFromContext returns the logged in user, or nil when unknown
+func UserFromContext(ctx context.Context) *User {
+	user, _ := ctx.Value(userKey).(*User)
+	return user
+}
+
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random string: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"Error": message})
+}